@@ -0,0 +1,116 @@
+// Package animation tweens element properties over time, driven by the
+// window's frame scheduler
+package animation
+
+import "math"
+
+// Easing maps linear progress in [0, 1] to eased progress
+type Easing func(t float64) float64
+
+// Linear progresses at a constant rate
+func Linear(t float64) float64 {
+	return t
+}
+
+// EaseInQuad starts slowly and accelerates
+func EaseInQuad(t float64) float64 {
+	return t * t
+}
+
+// EaseOutQuad starts quickly and decelerates
+func EaseOutQuad(t float64) float64 {
+	return t * (2 - t)
+}
+
+// EaseInOutQuad accelerates until halfway, then decelerates
+func EaseInOutQuad(t float64) float64 {
+	if t < 0.5 {
+		return 2 * t * t
+	}
+	return -1 + (4-2*t)*t
+}
+
+// EaseInCubic starts slowly and accelerates sharply
+func EaseInCubic(t float64) float64 {
+	return t * t * t
+}
+
+// EaseOutCubic starts quickly and decelerates smoothly
+func EaseOutCubic(t float64) float64 {
+	t--
+	return t*t*t + 1
+}
+
+// EaseInOutCubic accelerates until halfway, then decelerates, more sharply than quad
+func EaseInOutCubic(t float64) float64 {
+	if t < 0.5 {
+		return 4 * t * t * t
+	}
+	t = 2*t - 2
+	return t*t*t/2 + 1
+}
+
+// EaseOutBack overshoots the target slightly before settling
+func EaseOutBack(t float64) float64 {
+	const c1 = 1.70158
+	const c3 = c1 + 1
+	t--
+	return 1 + c3*t*t*t + c1*t*t
+}
+
+// EaseOutElastic oscillates around the target before settling
+func EaseOutElastic(t float64) float64 {
+	if t <= 0 || t >= 1 {
+		return t
+	}
+	return math.Pow(2, -10*t)*math.Sin((t*10-0.75)*(2*math.Pi/3)) + 1
+}
+
+// EaseOutBounce bounces against the target like a dropped ball
+func EaseOutBounce(t float64) float64 {
+	const n1 = 7.5625
+	const d1 = 2.75
+
+	switch {
+	case t < 1/d1:
+		return n1 * t * t
+	case t < 2/d1:
+		t -= 1.5 / d1
+		return n1*t*t + 0.75
+	case t < 2.5/d1:
+		t -= 2.25 / d1
+		return n1*t*t + 0.9375
+	default:
+		t -= 2.625 / d1
+		return n1*t*t + 0.984375
+	}
+}
+
+// CubicBezier returns a CSS-style easing through control points
+// (x1, y1) and (x2, y2), with the curve anchored at (0, 0) and (1, 1)
+func CubicBezier(x1, y1, x2, y2 float64) Easing {
+	bezier := func(t, p1, p2 float64) float64 {
+		u := 1 - t
+		return 3*u*u*t*p1 + 3*u*t*t*p2 + t*t*t
+	}
+
+	return func(x float64) float64 {
+		if x <= 0 || x >= 1 {
+			return x
+		}
+
+		// Solve bezier(t, x1, x2) = x by bisection, which is monotonic
+		// for control points with x in [0, 1]
+		lo, hi := 0.0, 1.0
+		t := x
+		for i := 0; i < 32; i++ {
+			if bezier(t, x1, x2) < x {
+				lo = t
+			} else {
+				hi = t
+			}
+			t = (lo + hi) / 2
+		}
+		return bezier(t, y1, y2)
+	}
+}
@@ -0,0 +1,116 @@
+package animation
+
+import "time"
+
+// Sequence runs animations one after another
+type Sequence struct {
+	animations []Animation
+	current    int
+	done       bool
+}
+
+// NewSequence creates an animation that plays each animation once the
+// previous one finished
+func NewSequence(animations ...Animation) *Sequence {
+	return &Sequence{animations: animations}
+}
+
+// Step advances the current animation, moving on to the next once it finishes
+func (s *Sequence) Step(now time.Time) bool {
+	for !s.done && s.current < len(s.animations) {
+		if s.animations[s.current].Step(now) {
+			return true
+		}
+		s.current++
+	}
+	s.done = true
+	return false
+}
+
+// Cancel stops the running animation and skips the rest
+func (s *Sequence) Cancel() {
+	if s.current < len(s.animations) {
+		s.animations[s.current].Cancel()
+	}
+	s.done = true
+}
+
+// Done reports whether the sequence finished or was cancelled
+func (s *Sequence) Done() bool {
+	return s.done
+}
+
+// Parallel runs animations together until all of them finished
+type Parallel struct {
+	animations []Animation
+	done       bool
+}
+
+// NewParallel creates an animation that plays all animations at once
+func NewParallel(animations ...Animation) *Parallel {
+	return &Parallel{animations: animations}
+}
+
+// Step advances every unfinished animation
+func (p *Parallel) Step(now time.Time) bool {
+	if p.done {
+		return false
+	}
+
+	running := false
+	for _, animation := range p.animations {
+		if !animation.Done() && animation.Step(now) {
+			running = true
+		}
+	}
+
+	p.done = !running
+	return running
+}
+
+// Cancel stops all animations
+func (p *Parallel) Cancel() {
+	for _, animation := range p.animations {
+		animation.Cancel()
+	}
+	p.done = true
+}
+
+// Done reports whether all animations finished or the group was cancelled
+func (p *Parallel) Done() bool {
+	return p.done
+}
+
+// Delay waits for a duration without changing anything, for use in sequences
+type Delay struct {
+	duration time.Duration
+	start    time.Time
+	done     bool
+}
+
+// NewDelay creates a pause of the given duration
+func NewDelay(duration time.Duration) *Delay {
+	return &Delay{duration: duration}
+}
+
+// Step reports whether the delay is still pending
+func (d *Delay) Step(now time.Time) bool {
+	if d.done {
+		return false
+	}
+	if d.start.IsZero() {
+		d.start = now
+	}
+	d.done = now.Sub(d.start) >= d.duration
+	return !d.done
+}
+
+// Cancel ends the delay early
+func (d *Delay) Cancel() {
+	d.done = true
+}
+
+// Done reports whether the delay elapsed or was cancelled
+func (d *Delay) Done() bool {
+	return d.done
+}
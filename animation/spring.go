@@ -0,0 +1,117 @@
+package animation
+
+import (
+	"math"
+	"time"
+)
+
+// SpringConfig describes the physical properties of a spring
+type SpringConfig struct {
+	Stiffness float64 // Force per unit of displacement
+	Damping   float64 // Force per unit of velocity
+	Mass      float64
+}
+
+// Common spring presets
+var (
+	SpringGentle = SpringConfig{Stiffness: 120, Damping: 14, Mass: 1}
+	SpringWobbly = SpringConfig{Stiffness: 180, Damping: 12, Mass: 1}
+	SpringStiff  = SpringConfig{Stiffness: 210, Damping: 20, Mass: 1}
+)
+
+// Spring drives a value towards a target with damped spring physics.
+// Unlike a Tween it has no fixed duration and can be retargeted mid-flight
+// while keeping its velocity.
+type Spring struct {
+	config    SpringConfig
+	value     float64
+	velocity  float64
+	target    float64
+	last      time.Time
+	done      bool
+	set       func(value float64)
+	precision float64
+}
+
+// maxSpringStep bounds integration steps so long frame gaps stay stable
+const maxSpringStep = time.Second / 120
+
+// NewSpring creates a spring moving a value from from to to
+func NewSpring(from, to float64, config SpringConfig, set func(value float64)) *Spring {
+	if config.Mass <= 0 {
+		config.Mass = 1
+	}
+	return &Spring{
+		config:    config,
+		value:     from,
+		target:    to,
+		set:       set,
+		precision: 0.01,
+	}
+}
+
+// SetTarget moves the spring's rest position and reports whether that
+// restarted a settled spring. The window drops animations once they finish,
+// so a restarted spring must be passed to Animate again.
+func (s *Spring) SetTarget(target float64) bool {
+	s.target = target
+	if !s.done {
+		return false
+	}
+	s.done = false
+	s.last = time.Time{} // Resume from the next frame, not the one it settled on
+	return true
+}
+
+// Value returns the spring's current value
+func (s *Spring) Value() float64 {
+	return s.value
+}
+
+// Step integrates the spring up to now and reports whether it is still moving
+func (s *Spring) Step(now time.Time) bool {
+	if s.done {
+		return false
+	}
+	if s.last.IsZero() {
+		s.last = now
+	}
+
+	elapsed := now.Sub(s.last)
+	s.last = now
+	if elapsed > time.Second/10 {
+		elapsed = time.Second / 10 // Don't jump after a stall
+	}
+
+	// Semi-implicit Euler in fixed sub-steps
+	for elapsed > 0 {
+		step := elapsed
+		if step > maxSpringStep {
+			step = maxSpringStep
+		}
+		elapsed -= step
+
+		dt := step.Seconds()
+		force := -s.config.Stiffness*(s.value-s.target) - s.config.Damping*s.velocity
+		s.velocity += force / s.config.Mass * dt
+		s.value += s.velocity * dt
+	}
+
+	if math.Abs(s.velocity) < s.precision && math.Abs(s.value-s.target) < s.precision {
+		s.value, s.velocity = s.target, 0
+		s.done = true
+	}
+
+	s.set(s.value)
+	return !s.done
+}
+
+// Cancel stops the spring where it is
+func (s *Spring) Cancel() {
+	s.done = true
+}
+
+// Done reports whether the spring settled or was cancelled
+func (s *Spring) Done() bool {
+	return s.done
+}
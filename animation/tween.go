@@ -0,0 +1,174 @@
+package animation
+
+import (
+	"image"
+	"math"
+	"time"
+
+	"github.com/lucasb-eyer/go-colorful"
+	"github.com/opd-ai/gui"
+)
+
+// Animation is a cancellable gui.Animation
+type Animation interface {
+	gui.Animation
+
+	// Cancel stops the animation where it is; Step returns false afterwards
+	Cancel()
+
+	// Done reports whether the animation finished or was cancelled
+	Done() bool
+}
+
+// Tween interpolates a property from a start to an end value over a fixed duration
+type Tween struct {
+	duration  time.Duration
+	easing    Easing
+	apply     func(progress float64)
+	start     time.Time
+	done      bool
+	cancelled bool
+	onDone    func()
+}
+
+// NewTween creates a tween that calls apply with eased progress in [0, 1]
+// on every frame; the clock starts on the first Step
+func NewTween(duration time.Duration, easing Easing, apply func(progress float64)) *Tween {
+	if easing == nil {
+		easing = Linear
+	}
+	return &Tween{
+		duration: duration,
+		easing:   easing,
+		apply:    apply,
+	}
+}
+
+// OnDone sets a callback run once the tween reaches its end value
+func (t *Tween) OnDone(callback func()) *Tween {
+	t.onDone = callback
+	return t
+}
+
+// Step advances the tween to now and reports whether it is still running
+func (t *Tween) Step(now time.Time) bool {
+	if t.done {
+		return false
+	}
+	if t.start.IsZero() {
+		t.start = now
+	}
+
+	progress := 1.0
+	if t.duration > 0 {
+		progress = math.Min(float64(now.Sub(t.start))/float64(t.duration), 1)
+	}
+
+	t.apply(t.easing(progress))
+
+	if progress >= 1 {
+		t.done = true
+		if t.onDone != nil {
+			t.onDone()
+		}
+		return false
+	}
+	return true
+}
+
+// Cancel stops the tween at its current value without running OnDone
+func (t *Tween) Cancel() {
+	t.done = true
+	t.cancelled = true
+}
+
+// Done reports whether the tween finished or was cancelled
+func (t *Tween) Done() bool {
+	return t.done
+}
+
+// Cancelled reports whether the tween was stopped before reaching its end value
+func (t *Tween) Cancelled() bool {
+	return t.cancelled
+}
+
+// Lerp interpolates linearly between a and b
+func Lerp(a, b, progress float64) float64 {
+	return a + (b-a)*progress
+}
+
+// Float tweens a float64 property
+func Float(from, to float64, duration time.Duration, easing Easing, set func(value float64)) *Tween {
+	return NewTween(duration, easing, func(p float64) {
+		set(Lerp(from, to, p))
+	})
+}
+
+// Int tweens an integer property, rounding intermediate values
+func Int(from, to int, duration time.Duration, easing Easing, set func(value int)) *Tween {
+	return NewTween(duration, easing, func(p float64) {
+		set(int(math.Round(Lerp(float64(from), float64(to), p))))
+	})
+}
+
+// Color tweens a colour property through the perceptually uniform Lab space
+func Color(from, to colorful.Color, duration time.Duration, easing Easing, set func(color colorful.Color)) *Tween {
+	return NewTween(duration, easing, func(p float64) {
+		set(from.BlendLab(to, p).Clamped())
+	})
+}
+
+// ColorHcl tweens a colour property through HCL space, keeping saturation
+// steady while the hue rotates
+func ColorHcl(from, to colorful.Color, duration time.Duration, easing Easing, set func(color colorful.Color)) *Tween {
+	return NewTween(duration, easing, func(p float64) {
+		set(from.BlendHcl(to, p).Clamped())
+	})
+}
+
+// Rect tweens a rectangle property, interpolating each corner
+func Rect(from, to image.Rectangle, duration time.Duration, easing Easing, set func(rect image.Rectangle)) *Tween {
+	return NewTween(duration, easing, func(p float64) {
+		lerp := func(a, b int) int {
+			return int(math.Round(Lerp(float64(a), float64(b), p)))
+		}
+		set(image.Rect(
+			lerp(from.Min.X, to.Min.X), lerp(from.Min.Y, to.Min.Y),
+			lerp(from.Max.X, to.Max.X), lerp(from.Max.Y, to.Max.Y),
+		))
+	})
+}
+
+// Sizer is an element whose size can be changed, such as any type embedding *gui.Element
+type Sizer interface {
+	gui.GUIElement
+	SetSize(width, height int)
+}
+
+// MoveTo tweens an element's position from where it is now to (x, y)
+func MoveTo(element gui.GUIElement, x, y int, duration time.Duration, easing Easing) *Tween {
+	fromX, fromY, _, _ := element.GetBounds()
+	return Rect(image.Rect(fromX, fromY, fromX, fromY), image.Rect(x, y, x, y), duration, easing,
+		func(r image.Rectangle) {
+			element.SetPosition(r.Min.X, r.Min.Y)
+		})
+}
+
+// ResizeTo tweens an element's size from its current size to width x height
+func ResizeTo(element Sizer, width, height int, duration time.Duration, easing Easing) *Tween {
+	_, _, fromWidth, fromHeight := element.GetBounds()
+	return Rect(image.Rect(0, 0, fromWidth, fromHeight), image.Rect(0, 0, width, height), duration, easing,
+		func(r image.Rectangle) {
+			element.SetSize(r.Dx(), r.Dy())
+		})
+}
+
+// BoundsTo tweens an element's position and size together
+func BoundsTo(element Sizer, bounds image.Rectangle, duration time.Duration, easing Easing) *Tween {
+	x, y, width, height := element.GetBounds()
+	return Rect(image.Rect(x, y, x+width, y+height), bounds, duration, easing,
+		func(r image.Rectangle) {
+			element.SetPosition(r.Min.X, r.Min.Y)
+			element.SetSize(r.Dx(), r.Dy())
+		})
+}
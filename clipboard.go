@@ -0,0 +1,128 @@
+package gui
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/png"
+	"sync"
+)
+
+// Clipboard MIME types understood by every backend
+const (
+	MIMETextPlain = "text/plain;charset=utf-8"
+	MIMEImagePNG  = "image/png"
+)
+
+// ErrClipboardFormat is returned when the clipboard holds no data of the requested type
+var ErrClipboardFormat = errors.New("clipboard format not available")
+
+// Clipboard exchanges data with other applications. Contents are offered
+// in one or more MIME types at once so readers can pick the richest one
+// they understand. Implementations are safe to use from any goroutine.
+type Clipboard interface {
+	// Formats returns the MIME types currently offered
+	Formats() ([]string, error)
+
+	// Read returns the contents in one MIME type, or ErrClipboardFormat
+	Read(mimeType string) ([]byte, error)
+
+	// Write replaces the contents with data keyed by MIME type
+	Write(data map[string][]byte) error
+}
+
+// MemoryClipboard is an in-process clipboard, used when the backend has
+// no system clipboard
+type MemoryClipboard struct {
+	mu   sync.RWMutex
+	data map[string][]byte
+}
+
+// NewMemoryClipboard creates an empty in-process clipboard
+func NewMemoryClipboard() *MemoryClipboard {
+	return &MemoryClipboard{data: make(map[string][]byte)}
+}
+
+// Formats returns the MIME types currently held
+func (c *MemoryClipboard) Formats() ([]string, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	formats := make([]string, 0, len(c.data))
+	for mimeType := range c.data {
+		formats = append(formats, mimeType)
+	}
+	return formats, nil
+}
+
+// Read returns a copy of the contents in one MIME type
+func (c *MemoryClipboard) Read(mimeType string) ([]byte, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	data, ok := c.data[mimeType]
+	if !ok {
+		return nil, ErrClipboardFormat
+	}
+	return append([]byte(nil), data...), nil
+}
+
+// Write replaces the contents with copies of data
+func (c *MemoryClipboard) Write(data map[string][]byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.data = make(map[string][]byte, len(data))
+	for mimeType, contents := range data {
+		c.data[mimeType] = append([]byte(nil), contents...)
+	}
+	return nil
+}
+
+// localClipboard is shared by every window whose backend has no system
+// clipboard, so text cut in one can be pasted in another
+var localClipboard = NewMemoryClipboard()
+
+// LocalClipboard returns the in-process clipboard used by windows whose
+// backend has no system clipboard
+func LocalClipboard() Clipboard {
+	return localClipboard
+}
+
+// Clipboard returns the window's clipboard: the backend's system clipboard,
+// or the in-process LocalClipboard when the backend has none
+func (w *Window) Clipboard() Clipboard {
+	return w.clipboard
+}
+
+// ReadClipboardText returns the clipboard's plain text
+func ReadClipboardText(clipboard Clipboard) (string, error) {
+	data, err := clipboard.Read(MIMETextPlain)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// WriteClipboardText replaces the clipboard contents with plain text
+func WriteClipboardText(clipboard Clipboard, text string) error {
+	return clipboard.Write(map[string][]byte{MIMETextPlain: []byte(text)})
+}
+
+// ReadClipboardImage decodes a PNG image from the clipboard
+func ReadClipboardImage(clipboard Clipboard) (image.Image, error) {
+	data, err := clipboard.Read(MIMEImagePNG)
+	if err != nil {
+		return nil, err
+	}
+	return png.Decode(bytes.NewReader(data))
+}
+
+// WriteClipboardImage replaces the clipboard contents with a PNG encoded image
+func WriteClipboardImage(clipboard Clipboard, img image.Image) error {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return err
+	}
+	return clipboard.Write(map[string][]byte{MIMEImagePNG: buf.Bytes()})
+}
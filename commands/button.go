@@ -0,0 +1,13 @@
+package commands
+
+import "github.com/opd-ai/gui/components"
+
+// BindButton makes a button run command when clicked and follow its enabled state
+func BindButton(button *components.Button, command *Command) *components.Button {
+	button.SetEnabled(command.IsEnabled())
+	button.SetOnClick(func() { command.Execute() })
+	command.OnEnabledChanged(func(enabled bool) {
+		button.SetEnabled(enabled)
+	})
+	return button
+}
@@ -0,0 +1,124 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/opd-ai/gui"
+)
+
+// chordModifiers are the modifier bits that take part in matching;
+// lock states such as CapsLock are ignored
+const chordModifiers = gui.ModifierShift | gui.ModifierCtrl | gui.ModifierAlt | gui.ModifierSuper
+
+// modifierAliases maps the modifier names accepted by ParseChord
+var modifierAliases = map[string]gui.KeyModifiers{
+	"ctrl":    gui.ModifierCtrl,
+	"control": gui.ModifierCtrl,
+	"shift":   gui.ModifierShift,
+	"alt":     gui.ModifierAlt,
+	"option":  gui.ModifierAlt,
+	"super":   gui.ModifierSuper,
+	"meta":    gui.ModifierSuper,
+	"cmd":     gui.ModifierSuper,
+	"win":     gui.ModifierSuper,
+}
+
+// Stroke is a single key press with the modifiers held during it
+type Stroke struct {
+	Key       gui.Key
+	Modifiers gui.KeyModifiers
+}
+
+// String returns the stroke in the form ParseChord accepts, such as "Ctrl+S"
+func (s Stroke) String() string {
+	if mods := s.Modifiers & chordModifiers; mods != gui.ModifierNone {
+		return mods.String() + "+" + s.Key.String()
+	}
+	return s.Key.String()
+}
+
+// Chord is a sequence of strokes, such as Ctrl+K followed by Ctrl+C
+type Chord []Stroke
+
+// ParseChord parses strokes separated by spaces, each made of modifier
+// names and a key name joined with "+", such as "Ctrl+K Ctrl+C"
+func ParseChord(text string) (Chord, error) {
+	fields := strings.Fields(text)
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("empty key chord")
+	}
+
+	chord := make(Chord, 0, len(fields))
+	for _, field := range fields {
+		stroke, err := parseStroke(field)
+		if err != nil {
+			return nil, fmt.Errorf("key chord %q: %w", text, err)
+		}
+		chord = append(chord, stroke)
+	}
+	return chord, nil
+}
+
+// MustParseChord is like ParseChord but panics on malformed input; it is
+// meant for chords written in source code
+func MustParseChord(text string) Chord {
+	chord, err := ParseChord(text)
+	if err != nil {
+		panic(err)
+	}
+	return chord
+}
+
+// parseStroke parses one "Ctrl+Shift+K" style stroke
+func parseStroke(text string) (Stroke, error) {
+	parts := strings.Split(text, "+")
+	var stroke Stroke
+
+	for _, part := range parts[:len(parts)-1] {
+		mod, ok := modifierAliases[strings.ToLower(part)]
+		if !ok {
+			return Stroke{}, fmt.Errorf("unknown modifier %q", part)
+		}
+		stroke.Modifiers |= mod
+	}
+
+	name := parts[len(parts)-1]
+	key, ok := gui.ParseKey(name)
+	if !ok || key == gui.KeyUnknown {
+		return Stroke{}, fmt.Errorf("unknown key %q", name)
+	}
+	if key.Modifier() != gui.ModifierNone {
+		return Stroke{}, fmt.Errorf("modifier key %q cannot end a stroke", name)
+	}
+	stroke.Key = key
+	return stroke, nil
+}
+
+// String returns the chord in the form ParseChord accepts
+func (c Chord) String() string {
+	strokes := make([]string, len(c))
+	for i, stroke := range c {
+		strokes[i] = stroke.String()
+	}
+	return strings.Join(strokes, " ")
+}
+
+// Equal reports whether two chords have the same strokes
+func (c Chord) Equal(other Chord) bool {
+	return len(c) == len(other) && c.HasPrefix(other)
+}
+
+// HasPrefix reports whether the chord starts with the strokes of prefix
+func (c Chord) HasPrefix(prefix Chord) bool {
+	if len(prefix) > len(c) {
+		return false
+	}
+	for i := range prefix {
+		if c[i].Key != prefix[i].Key ||
+			c[i].Modifiers&chordModifiers != prefix[i].Modifiers&chordModifiers {
+			return false
+		}
+	}
+	return true
+}
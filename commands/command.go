@@ -0,0 +1,53 @@
+package commands
+
+// Command is a named action shared by key bindings, menus, toolbars and
+// buttons; like components, it is only used on the UI loop
+type Command struct {
+	name      string
+	title     string
+	run       func()
+	enabled   bool
+	observers []func(enabled bool)
+}
+
+// Name returns the command's unique name, such as "file.save"
+func (c *Command) Name() string {
+	return c.name
+}
+
+// Title returns the human readable title shown in menus
+func (c *Command) Title() string {
+	return c.title
+}
+
+// SetEnabled enables or disables the command and notifies everything bound to it
+func (c *Command) SetEnabled(enabled bool) *Command {
+	if c.enabled == enabled {
+		return c
+	}
+	c.enabled = enabled
+	for _, observer := range c.observers {
+		observer(enabled)
+	}
+	return c
+}
+
+// IsEnabled returns whether the command can run
+func (c *Command) IsEnabled() bool {
+	return c.enabled
+}
+
+// OnEnabledChanged registers a callback run whenever the enabled state changes
+func (c *Command) OnEnabledChanged(callback func(enabled bool)) *Command {
+	c.observers = append(c.observers, callback)
+	return c
+}
+
+// Execute runs the command if it is enabled and reports whether it ran
+func (c *Command) Execute() bool {
+	if !c.enabled || c.run == nil {
+		return false
+	}
+	c.run()
+	return true
+}
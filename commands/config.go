@@ -0,0 +1,64 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+)
+
+// LoadBindings reads user key bindings as a JSON object mapping command
+// names to lists of chords, such as {"file.save": ["Ctrl+S"]}, and applies
+// them with Rebind. Names of commands not registered yet are kept for later.
+func (r *Registry) LoadBindings(reader io.Reader) error {
+	var config map[string][]string
+	if err := json.NewDecoder(reader).Decode(&config); err != nil {
+		return fmt.Errorf("load key bindings: %w", err)
+	}
+
+	// Validate everything before changing any binding
+	names := make([]string, 0, len(config))
+	for name, chords := range config {
+		for _, chord := range chords {
+			if _, err := ParseChord(chord); err != nil {
+				return fmt.Errorf("load key bindings: %q: %w", name, err)
+			}
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if err := r.Rebind(name, config[name]...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// LoadBindingsFile reads user key bindings from a JSON file
+func (r *Registry) LoadBindingsFile(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("load key bindings: %w", err)
+	}
+	defer file.Close()
+	return r.LoadBindings(file)
+}
+
+// SaveBindings writes the user rebindings in the format LoadBindings reads
+func (r *Registry) SaveBindings(writer io.Writer) error {
+	config := make(map[string][]string, len(r.overrides))
+	for name, chords := range r.overrides {
+		keys := make([]string, len(chords))
+		for i, chord := range chords {
+			keys[i] = chord.String()
+		}
+		config[name] = keys
+	}
+
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(config)
+}
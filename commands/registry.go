@@ -0,0 +1,258 @@
+package commands
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/opd-ai/gui"
+)
+
+// DefaultChordTimeout is how long a multi-stroke chord waits for its next stroke
+const DefaultChordTimeout = 1500 * time.Millisecond
+
+// Binding attaches a key chord to a command within a scope
+type Binding struct {
+	Command *Command
+	Chord   Chord
+	Scope   Scope
+	User    bool // Set for bindings loaded from user configuration
+}
+
+// Conflict describes a binding hidden by another one for the same keys
+type Conflict struct {
+	Winner   Binding
+	Shadowed Binding
+}
+
+// Registry holds a window's commands and key bindings and runs commands
+// for key presses before the focused element sees them; it is only used
+// on the UI loop
+type Registry struct {
+	window       *gui.Window
+	commands     map[string]*Command
+	order        []string             // Command names in registration order
+	defaults     map[string][]Binding // Bindings declared by the application
+	overrides    map[string][]Chord   // Chords set by the user, replacing the defaults
+	chordTimeout time.Duration
+	pending      Chord // Strokes typed so far of a multi-stroke chord
+	pendingAt    time.Time
+}
+
+// NewRegistry creates a command registry and attaches it to the window
+func NewRegistry(window *gui.Window) *Registry {
+	r := &Registry{
+		window:       window,
+		commands:     make(map[string]*Command),
+		defaults:     make(map[string][]Binding),
+		overrides:    make(map[string][]Chord),
+		chordTimeout: DefaultChordTimeout,
+	}
+
+	// A capture handler on the window runs before the focused element's handlers
+	window.AddCaptureHandler(gui.EventTypeKeyPress, gui.EventHandlerFunc(r.handleKeyPress))
+	return r
+}
+
+// SetChordTimeout sets how long a multi-stroke chord waits for its next stroke
+func (r *Registry) SetChordTimeout(timeout time.Duration) *Registry {
+	r.chordTimeout = timeout
+	return r
+}
+
+// Register adds an enabled command, or updates the title and action of
+// an existing one while keeping its bindings and enabled state
+func (r *Registry) Register(name, title string, run func()) *Command {
+	if command, ok := r.commands[name]; ok {
+		command.title = title
+		command.run = run
+		return command
+	}
+
+	command := &Command{name: name, title: title, run: run, enabled: true}
+	r.commands[name] = command
+	r.order = append(r.order, name)
+	return command
+}
+
+// Command returns the command registered under name, or nil
+func (r *Registry) Command(name string) *Command {
+	return r.commands[name]
+}
+
+// Execute runs the named command and reports whether it ran
+func (r *Registry) Execute(name string) bool {
+	command := r.commands[name]
+	return command != nil && command.Execute()
+}
+
+// Bind adds a default key chord for a registered command
+func (r *Registry) Bind(name, chord string, scope Scope) error {
+	command := r.commands[name]
+	if command == nil {
+		return fmt.Errorf("bind %q: unknown command", name)
+	}
+	parsed, err := ParseChord(chord)
+	if err != nil {
+		return fmt.Errorf("bind %q: %w", name, err)
+	}
+	r.defaults[name] = append(r.defaults[name], Binding{Command: command, Chord: parsed, Scope: scope})
+	return nil
+}
+
+// Unbind removes all default key chords of a command
+func (r *Registry) Unbind(name string) {
+	delete(r.defaults, name)
+}
+
+// Rebind replaces a command's chords with user chosen ones, which keep
+// the scope of its default bindings; no chords leaves the command unbound
+func (r *Registry) Rebind(name string, chords ...string) error {
+	parsed := make([]Chord, 0, len(chords))
+	for _, chord := range chords {
+		c, err := ParseChord(chord)
+		if err != nil {
+			return fmt.Errorf("rebind %q: %w", name, err)
+		}
+		parsed = append(parsed, c)
+	}
+	r.overrides[name] = parsed
+	return nil
+}
+
+// ResetBinding drops a user rebinding, restoring the command's default chords
+func (r *Registry) ResetBinding(name string) {
+	delete(r.overrides, name)
+}
+
+// Bindings returns the effective bindings of a command
+func (r *Registry) Bindings(name string) []Binding {
+	command := r.commands[name]
+	if command == nil {
+		return nil
+	}
+
+	defaults := r.defaults[name]
+	chords, overridden := r.overrides[name]
+	if !overridden {
+		return append([]Binding(nil), defaults...)
+	}
+
+	scope := WindowScope()
+	if len(defaults) > 0 {
+		scope = defaults[0].Scope
+	}
+	bindings := make([]Binding, len(chords))
+	for i, chord := range chords {
+		bindings[i] = Binding{Command: command, Chord: chord, Scope: scope, User: true}
+	}
+	return bindings
+}
+
+// active returns the effective bindings of all commands in registration order
+func (r *Registry) active() []Binding {
+	var bindings []Binding
+	for _, name := range r.order {
+		bindings = append(bindings, r.Bindings(name)...)
+	}
+	return bindings
+}
+
+// beats reports whether binding a takes precedence over b for the same keys:
+// narrower scopes win, then user bindings, then later registrations
+func beats(a, b Binding) bool {
+	if a.Scope.narrowerThan(b.Scope) {
+		return true
+	}
+	if b.Scope.narrowerThan(a.Scope) {
+		return false
+	}
+	return a.User || !b.User
+}
+
+// Conflicts lists bindings that can never fire because another binding in
+// the same scope uses the same chord, or a longer chord starting with it
+func (r *Registry) Conflicts() []Conflict {
+	bindings := r.active()
+	var conflicts []Conflict
+
+	for i, a := range bindings {
+		for _, b := range bindings[i+1:] {
+			if a.Scope != b.Scope {
+				continue
+			}
+			switch {
+			case a.Chord.Equal(b.Chord):
+				if beats(b, a) {
+					conflicts = append(conflicts, Conflict{Winner: b, Shadowed: a})
+				} else {
+					conflicts = append(conflicts, Conflict{Winner: a, Shadowed: b})
+				}
+			case a.Chord.HasPrefix(b.Chord):
+				conflicts = append(conflicts, Conflict{Winner: a, Shadowed: b})
+			case b.Chord.HasPrefix(a.Chord):
+				conflicts = append(conflicts, Conflict{Winner: b, Shadowed: a})
+			}
+		}
+	}
+	return conflicts
+}
+
+// Pending returns the strokes typed so far of an unfinished multi-stroke chord
+func (r *Registry) Pending() Chord {
+	return append(Chord(nil), r.pending...)
+}
+
+// match finds the binding to run for a sequence of strokes and whether a
+// longer chord could still complete it; only enabled commands whose scope
+// applies to the focused element take part
+func (r *Registry) match(sequence Chord, focused gui.GUIElement) (best *Binding, prefix bool) {
+	bindings := r.active()
+	for i := range bindings {
+		b := &bindings[i]
+		if !b.Command.IsEnabled() || !b.Scope.appliesTo(focused) {
+			continue
+		}
+		switch {
+		case b.Chord.Equal(sequence):
+			if best == nil || beats(*b, *best) {
+				best = b
+			}
+		case b.Chord.HasPrefix(sequence):
+			prefix = true
+		}
+	}
+	return best, prefix
+}
+
+// handleKeyPress runs the command bound to a key press, holding the first
+// strokes of a multi-stroke chord until it completes or times out
+func (r *Registry) handleKeyPress(event gui.Event) bool {
+	keyEvent := event.(*gui.KeyPressEvent)
+	if event.IsDefaultPrevented() || keyEvent.Key.Modifier() != gui.ModifierNone {
+		return false // Holding a modifier never ends a stroke
+	}
+
+	now := keyEvent.Timestamp()
+	if len(r.pending) > 0 && now.Sub(r.pendingAt) > r.chordTimeout {
+		r.pending = nil
+	}
+
+	sequence := append(r.Pending(), Stroke{Key: keyEvent.Key, Modifiers: keyEvent.Modifiers & chordModifiers})
+	best, prefix := r.match(sequence, r.window.FocusManager().Focused())
+
+	switch {
+	case prefix:
+		// A longer chord wins over an exact match of its first strokes
+		r.pending = sequence
+		r.pendingAt = now
+		return true
+	case best != nil:
+		r.pending = nil
+		best.Command.Execute()
+		return true
+	case len(r.pending) > 0:
+		r.pending = nil
+		return true // The stroke broke a chord, so nothing else gets it
+	}
+	return false
+}
@@ -0,0 +1,77 @@
+package commands
+
+import "github.com/opd-ai/gui"
+
+// ScopeKind says where a key binding is active
+type ScopeKind int
+
+const (
+	// ScopeWindow bindings are active whatever has focus
+	ScopeWindow ScopeKind = iota
+	// ScopeFocus bindings are active while focus is inside a subtree
+	ScopeFocus
+	// ScopeElement bindings are active only while one element has focus
+	ScopeElement
+)
+
+// Scope limits a key binding to part of the window
+type Scope struct {
+	Kind    ScopeKind
+	Element gui.GUIElement // Subtree root or focused element; unused for ScopeWindow
+}
+
+// WindowScope returns a scope active everywhere in the window
+func WindowScope() Scope {
+	return Scope{Kind: ScopeWindow}
+}
+
+// FocusScope returns a scope active while root or one of its descendants has focus
+func FocusScope(root gui.GUIElement) Scope {
+	return Scope{Kind: ScopeFocus, Element: root}
+}
+
+// ElementScope returns a scope active only while element itself has focus
+func ElementScope(element gui.GUIElement) Scope {
+	return Scope{Kind: ScopeElement, Element: element}
+}
+
+// ancestor is implemented by elements embedding *gui.Element
+type ancestor interface {
+	IsAncestorOf(el gui.GUIElement) bool
+}
+
+// isWithin reports whether el is root or one of its descendants
+func isWithin(root, el gui.GUIElement) bool {
+	if el == nil {
+		return false
+	}
+	if root == el {
+		return true
+	}
+	a, ok := root.(ancestor)
+	return ok && a.IsAncestorOf(el)
+}
+
+// appliesTo reports whether the scope is active with focused having focus
+func (s Scope) appliesTo(focused gui.GUIElement) bool {
+	switch s.Kind {
+	case ScopeFocus:
+		return isWithin(s.Element, focused)
+	case ScopeElement:
+		return focused != nil && s.Element == focused
+	}
+	return true
+}
+
+// narrowerThan reports whether the scope is more specific than other:
+// element scopes beat focus scopes, which beat the window, and a focus
+// scope nested inside another beats the outer one
+func (s Scope) narrowerThan(other Scope) bool {
+	if s.Kind != other.Kind {
+		return s.Kind > other.Kind
+	}
+	if s.Kind == ScopeFocus && s.Element != other.Element {
+		return isWithin(other.Element, s.Element)
+	}
+	return false
+}
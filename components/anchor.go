@@ -0,0 +1,416 @@
+package components
+
+import (
+	"fmt"
+	"image"
+
+	"github.com/opd-ai/gui"
+)
+
+// Edge is a side or center line of an element
+type Edge int
+
+const (
+	EdgeLeft Edge = iota
+	EdgeRight
+	EdgeCenterX
+	EdgeTop
+	EdgeBottom
+	EdgeCenterY
+)
+
+// horizontal reports whether the edge constrains x positions
+func (e Edge) horizontal() bool {
+	return e <= EdgeCenterX
+}
+
+// Anchor ties an edge of a child to an edge of the parent or a sibling:
+// the child's edge sits at the target edge plus Offset
+type Anchor struct {
+	Target gui.GUIElement // nil for the parent
+	Edge   Edge
+	Offset int
+}
+
+// ToParent anchors to an edge of the parent's content area
+func ToParent(edge Edge, offset int) Anchor {
+	return Anchor{Edge: edge, Offset: offset}
+}
+
+// To anchors to an edge of a sibling
+func To(target gui.GUIElement, edge Edge, offset int) Anchor {
+	return Anchor{Target: target, Edge: edge, Offset: offset}
+}
+
+// AnchorLayout places children by anchoring their edges to the parent or
+// to siblings. Anchoring both sides of an axis stretches the child; one
+// edge keeps its measured size; an axis without anchors keeps the position
+// set with SetPosition.
+type AnchorLayout struct {
+	anchors map[gui.GUIElement]map[Edge]Anchor
+}
+
+// NewAnchorLayout creates an anchor layout without anchors
+func NewAnchorLayout() *AnchorLayout {
+	return &AnchorLayout{anchors: make(map[gui.GUIElement]map[Edge]Anchor)}
+}
+
+// SetAnchor anchors an edge of a child. Anchors must stay on one axis and
+// must not form a cycle through siblings.
+func (l *AnchorLayout) SetAnchor(child gui.GUIElement, edge Edge, anchor Anchor) error {
+	if edge.horizontal() != anchor.Edge.horizontal() {
+		return fmt.Errorf("anchor joins a horizontal and a vertical edge")
+	}
+	if anchor.Target == child || l.dependsOn(anchor.Target, child, edge.horizontal()) {
+		return fmt.Errorf("anchor would form a cycle")
+	}
+	if l.anchors[child] == nil {
+		l.anchors[child] = make(map[Edge]Anchor)
+	}
+	l.anchors[child][edge] = anchor
+	return nil
+}
+
+// ClearAnchor removes the anchor from an edge of a child
+func (l *AnchorLayout) ClearAnchor(child gui.GUIElement, edge Edge) {
+	delete(l.anchors[child], edge)
+}
+
+// AnchorOf returns the anchor on an edge of a child, if any
+func (l *AnchorLayout) AnchorOf(child gui.GUIElement, edge Edge) (Anchor, bool) {
+	anchor, ok := l.anchors[child][edge]
+	return anchor, ok
+}
+
+// ChildRemoved forgets the anchors of a child that left the container and
+// the siblings' anchors to it
+func (l *AnchorLayout) ChildRemoved(child gui.GUIElement) {
+	delete(l.anchors, child)
+	for _, edges := range l.anchors {
+		for edge, anchor := range edges {
+			if anchor.Target == child {
+				delete(edges, edge)
+			}
+		}
+	}
+}
+
+// dependsOn reports whether el is placed, directly or through other
+// siblings, relative to target on one axis
+func (l *AnchorLayout) dependsOn(el, target gui.GUIElement, horizontal bool) bool {
+	if el == nil {
+		return false
+	}
+	for edge, anchor := range l.anchors[el] {
+		if edge.horizontal() != horizontal || anchor.Target == nil {
+			continue
+		}
+		if anchor.Target == target || l.dependsOn(anchor.Target, target, horizontal) {
+			return true
+		}
+	}
+	return false
+}
+
+// anchorSpan is a child's extent along one axis while solving
+type anchorSpan struct {
+	start, end int
+	state      int // 0 unsolved, 1 solving, 2 solved
+}
+
+// anchorSolver resolves every child's extent on one axis, solving the
+// siblings a child is anchored to before the child
+type anchorSolver struct {
+	layout     *AnchorLayout
+	horizontal bool
+	size       int // Parent content size along the axis
+	other      int // Parent content size across the axis, for measuring
+	origin     image.Point
+	spans      map[gui.GUIElement]*anchorSpan
+}
+
+// solve returns the extent of a child, solving it first if needed
+func (s *anchorSolver) solve(el gui.GUIElement) *anchorSpan {
+	span, ok := s.spans[el]
+	if !ok {
+		// Not laid out here, such as a hidden sibling: use where it is
+		x, y, w, h := el.GetBounds()
+		x, y = x-s.origin.X, y-s.origin.Y
+		if s.horizontal {
+			return &anchorSpan{start: x, end: x + w, state: 2}
+		}
+		return &anchorSpan{start: y, end: y + h, state: 2}
+	}
+	if span.state != 0 {
+		return span // A cycle, refused by SetAnchor, cannot recurse forever
+	}
+	span.state = 1
+
+	startEdge, endEdge, centerEdge := EdgeLeft, EdgeRight, EdgeCenterX
+	if !s.horizontal {
+		startEdge, endEdge, centerEdge = EdgeTop, EdgeBottom, EdgeCenterY
+	}
+	anchors := s.layout.anchors[el]
+	startAnchor, hasStart := anchors[startEdge]
+	endAnchor, hasEnd := anchors[endEdge]
+	centerAnchor, hasCenter := anchors[centerEdge]
+
+	x, y, _, _ := el.GetBounds()
+	pos := y - s.origin.Y
+	if s.horizontal {
+		pos = x - s.origin.X
+	}
+
+	// Anchored on both sides, a child stretches; otherwise it keeps its
+	// measured size
+	size := s.measure(el)
+	switch {
+	case hasStart && hasEnd:
+		start, end := s.edge(startAnchor), s.edge(endAnchor)
+		span.start, span.end = start, start+s.clamp(el, end-start)
+	case hasStart && hasCenter:
+		start, center := s.edge(startAnchor), s.edge(centerAnchor)
+		span.start, span.end = start, start+s.clamp(el, 2*(center-start))
+	case hasEnd && hasCenter:
+		end, center := s.edge(endAnchor), s.edge(centerAnchor)
+		length := s.clamp(el, 2*(end-center))
+		span.start, span.end = end-length, end
+	case hasStart:
+		span.start = s.edge(startAnchor)
+		span.end = span.start + size
+	case hasEnd:
+		span.end = s.edge(endAnchor)
+		span.start = span.end - size
+	case hasCenter:
+		span.start = s.edge(centerAnchor) - size/2
+		span.end = span.start + size
+	default:
+		span.start, span.end = pos, pos+size
+	}
+	span.state = 2
+	return span
+}
+
+// edge returns where an anchor puts an edge
+func (s *anchorSolver) edge(anchor Anchor) int {
+	var start, end int
+	if anchor.Target == nil {
+		start, end = 0, s.size
+	} else {
+		target := s.solve(anchor.Target)
+		start, end = target.start, target.end
+	}
+
+	switch anchor.Edge {
+	case EdgeLeft, EdgeTop:
+		return start + anchor.Offset
+	case EdgeRight, EdgeBottom:
+		return end + anchor.Offset
+	}
+	return (start+end)/2 + anchor.Offset
+}
+
+// measure returns a child's natural length along the axis
+func (s *anchorSolver) measure(el gui.GUIElement) int {
+	if s.horizontal {
+		return gui.MeasureElement(el, gui.LooseConstraints(gui.Unbounded, s.other)).Width
+	}
+	return gui.MeasureElement(el, gui.LooseConstraints(s.other, gui.Unbounded)).Height
+}
+
+// clamp keeps a stretched length within a child's minimum and maximum
+func (s *anchorSolver) clamp(el gui.GUIElement, length int) int {
+	if sizer, ok := el.(interface{ MaxSize() (int, int) }); ok {
+		w, h := sizer.MaxSize()
+		max := h
+		if s.horizontal {
+			max = w
+		}
+		if max > 0 && length > max {
+			length = max
+		}
+	}
+	if sizer, ok := el.(interface{ MinSize() (int, int) }); ok {
+		w, h := sizer.MinSize()
+		min := h
+		if s.horizontal {
+			min = w
+		}
+		if length < min {
+			length = min
+		}
+	}
+	if length < 0 {
+		length = 0
+	}
+	return length
+}
+
+// solveAxis returns the extent of every child along one axis
+func (l *AnchorLayout) solveAxis(children []gui.GUIElement, horizontal bool, area image.Rectangle) map[gui.GUIElement]*anchorSpan {
+	s := &anchorSolver{
+		layout:     l,
+		horizontal: horizontal,
+		size:       area.Dy(),
+		other:      area.Dx(),
+		origin:     area.Min,
+		spans:      make(map[gui.GUIElement]*anchorSpan, len(children)),
+	}
+	if horizontal {
+		s.size, s.other = area.Dx(), area.Dy()
+	}
+	for _, child := range children {
+		s.spans[child] = &anchorSpan{}
+	}
+	for _, child := range children {
+		s.solve(child)
+	}
+	return s.spans
+}
+
+// Measure returns the smallest size at which every child anchored to the
+// parent gets its natural size and none sticks out
+func (l *AnchorLayout) Measure(children []gui.GUIElement, constraints gui.Constraints) gui.Size {
+	width := clampConstraint(l.needed(children, true, constraints.MaxHeight), constraints.MinWidth, constraints.MaxWidth)
+	height := clampConstraint(l.needed(children, false, width), constraints.MinHeight, constraints.MaxHeight)
+	area := image.Rect(0, 0, width, height)
+	return gui.Size{
+		Width:  extent(l.solveAxis(children, true, area), width),
+		Height: extent(l.solveAxis(children, false, area), height),
+	}
+}
+
+// needed returns the parent length along an axis that fits the natural
+// size of every child anchored only to the parent
+func (l *AnchorLayout) needed(children []gui.GUIElement, horizontal bool, other int) int {
+	s := &anchorSolver{horizontal: horizontal, other: other}
+	startEdge, endEdge, centerEdge := EdgeLeft, EdgeRight, EdgeCenterX
+	if !horizontal {
+		startEdge, endEdge, centerEdge = EdgeTop, EdgeBottom, EdgeCenterY
+	}
+
+	need := 0
+	for _, child := range children {
+		anchors := l.anchors[child]
+		start, hasStart := anchors[startEdge]
+		end, hasEnd := anchors[endEdge]
+		center, hasCenter := anchors[centerEdge]
+		if (hasStart && (start.Target != nil || start.Edge != startEdge)) ||
+			(hasEnd && (end.Target != nil || end.Edge != endEdge)) ||
+			(hasCenter && (center.Target != nil || center.Edge != centerEdge)) {
+			continue // Placed relative to siblings, left to the solved extent
+		}
+
+		size := s.measure(child)
+		n := 0
+		switch {
+		case hasStart && hasEnd:
+			n = start.Offset + size - end.Offset
+		case hasStart:
+			n = start.Offset + size
+		case hasEnd:
+			n = size - end.Offset
+		case hasCenter:
+			n = size + 2*absInt(center.Offset)
+		}
+		if n > need {
+			need = n
+		}
+	}
+	return need
+}
+
+// clampConstraint keeps a length within a minimum and maximum
+func clampConstraint(v, min, max int) int {
+	if v > max {
+		v = max
+	}
+	if v < min {
+		v = min
+	}
+	return v
+}
+
+func absInt(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+// extent grows a size until it holds every span
+func extent(spans map[gui.GUIElement]*anchorSpan, size int) int {
+	low, high := 0, size
+	for _, span := range spans {
+		if span.start < low {
+			low = span.start
+		}
+		if span.end > high {
+			high = span.end
+		}
+	}
+	return high - low
+}
+
+// Arrange solves both axes for the area and places the children
+func (l *AnchorLayout) Arrange(children []gui.GUIElement, area image.Rectangle) {
+	xs := l.solveAxis(children, true, area)
+	ys := l.solveAxis(children, false, area)
+	for _, child := range children {
+		x, y := xs[child], ys[child]
+		bounds := image.Rect(x.start, y.start, x.end, y.end).Add(area.Min)
+		gui.ArrangeElement(child, bounds)
+	}
+}
+
+// AnchorPanel is a container laying out its children with an AnchorLayout
+type AnchorPanel struct {
+	*gui.Element
+	layout *AnchorLayout
+}
+
+// NewAnchorPanel creates an empty anchor panel
+func NewAnchorPanel() *AnchorPanel {
+	panel := &AnchorPanel{
+		Element: gui.NewElement(0, 0, 0, 0),
+		layout:  NewAnchorLayout(),
+	}
+	panel.SetLayout(panel.layout)
+	return panel
+}
+
+// Anchor anchors an edge of a child, adding the child if needed. It
+// panics on anchors SetAnchor refuses, as those are programming errors.
+func (p *AnchorPanel) Anchor(child gui.GUIElement, edge Edge, anchor Anchor) *AnchorPanel {
+	if err := p.layout.SetAnchor(child, edge, anchor); err != nil {
+		panic("components: " + err.Error())
+	}
+	if p.ChildIndex(child) < 0 {
+		p.AddChild(child)
+	}
+	p.InvalidateLayout()
+	return p
+}
+
+// AnchorToParent anchors each of the given edges of a child to the same
+// edge of the panel, inset by margin
+func (p *AnchorPanel) AnchorToParent(child gui.GUIElement, margin int, edges ...Edge) *AnchorPanel {
+	for _, edge := range edges {
+		offset := margin
+		switch edge {
+		case EdgeRight, EdgeBottom:
+			offset = -margin
+		case EdgeCenterX, EdgeCenterY:
+			offset = 0
+		}
+		p.Anchor(child, edge, ToParent(edge, offset))
+	}
+	return p
+}
+
+// ClearAnchor removes the anchor from an edge of a child
+func (p *AnchorPanel) ClearAnchor(child gui.GUIElement, edge Edge) *AnchorPanel {
+	p.layout.ClearAnchor(child, edge)
+	p.InvalidateLayout()
+	return p
+}
@@ -0,0 +1,515 @@
+package components
+
+import (
+	"image"
+	"math"
+
+	"github.com/opd-ai/gui"
+)
+
+// BoxDirection is the axis a box lays its children out along
+type BoxDirection int
+
+const (
+	BoxRow BoxDirection = iota
+	BoxColumn
+)
+
+// JustifyContent controls how free space along the main axis is shared
+type JustifyContent int
+
+const (
+	JustifyStart JustifyContent = iota
+	JustifyEnd
+	JustifyCenter
+	JustifySpaceBetween
+	JustifySpaceAround
+	JustifySpaceEvenly
+)
+
+// AlignItems controls how children are placed across the main axis
+type AlignItems int
+
+const (
+	AlignItemsStretch AlignItems = iota
+	AlignItemsStart
+	AlignItemsEnd
+	AlignItemsCenter
+)
+
+// BasisAuto sizes a child from its measured size before growing or shrinking
+const BasisAuto = -1
+
+// Flex controls how a child of a box takes part in sharing space. Grow and
+// Shrink are weights for taking free space and giving up overflow; Basis is
+// the main axis size before that, or BasisAuto.
+type Flex struct {
+	Grow   float64
+	Shrink float64
+	Basis  int
+}
+
+// DefaultFlex keeps a child at its measured size, shrinking it on overflow
+var DefaultFlex = Flex{Grow: 0, Shrink: 1, Basis: BasisAuto}
+
+// BoxLayout is a flexbox-style layout placing children in a row or column,
+// optionally wrapping them onto more lines
+type BoxLayout struct {
+	Direction BoxDirection
+	Wrap      bool
+	Gap       int // Space between children, and between wrapped lines
+	Justify   JustifyContent
+	Align     AlignItems
+
+	flex map[gui.GUIElement]Flex
+}
+
+// NewBoxLayout creates a box layout in the given direction
+func NewBoxLayout(direction BoxDirection) *BoxLayout {
+	return &BoxLayout{
+		Direction: direction,
+		flex:      make(map[gui.GUIElement]Flex),
+	}
+}
+
+// SetFlex sets how a child grows and shrinks
+func (l *BoxLayout) SetFlex(child gui.GUIElement, flex Flex) {
+	l.flex[child] = flex
+}
+
+// FlexOf returns a child's flex, DefaultFlex unless set
+func (l *BoxLayout) FlexOf(child gui.GUIElement) Flex {
+	if flex, ok := l.flex[child]; ok {
+		return flex
+	}
+	return DefaultFlex
+}
+
+// ChildRemoved forgets the flex of a child that left the container
+func (l *BoxLayout) ChildRemoved(child gui.GUIElement) {
+	delete(l.flex, child)
+}
+
+// boxItem is a child with its sizes along the two axes
+type boxItem struct {
+	el                    gui.GUIElement
+	flex                  Flex
+	marginStart           int // Margin before the child on the main axis
+	marginEnd             int
+	crossStart            int // Margin before the child on the cross axis
+	crossEnd              int
+	base                  int // Main size before flexing, without margins
+	cross                 int // Measured cross size, without margins
+	minMain, maxMain      int
+	maxCross              int
+	finalMain, finalCross int
+}
+
+func (it *boxItem) outerMain() int       { return it.base + it.marginStart + it.marginEnd }
+func (it *boxItem) outerCross() int      { return it.cross + it.crossStart + it.crossEnd }
+func (it *boxItem) outerCrossFinal() int { return it.finalCross + it.crossStart + it.crossEnd }
+
+// boxLine is a run of items sharing one line of a wrapping box
+type boxLine struct {
+	items []*boxItem
+	cross int
+}
+
+// Measure returns the size of the lines the children wrap into
+func (l *BoxLayout) Measure(children []gui.GUIElement, constraints gui.Constraints) gui.Size {
+	mainMax, crossMax := l.main(constraints.MaxWidth, constraints.MaxHeight), l.cross(constraints.MaxWidth, constraints.MaxHeight)
+	lines := l.lines(l.items(children, crossMax), mainMax)
+
+	main, cross := 0, 0
+	for i, line := range lines {
+		lineMain := l.Gap * (len(line.items) - 1)
+		for _, it := range line.items {
+			lineMain += it.outerMain()
+		}
+		if lineMain > main {
+			main = lineMain
+		}
+		if i > 0 {
+			cross += l.Gap
+		}
+		cross += line.cross
+	}
+	return l.size(main, cross)
+}
+
+// Arrange flexes each line to the area's main size and places the children
+func (l *BoxLayout) Arrange(children []gui.GUIElement, area image.Rectangle) {
+	areaMain, areaCross := l.main(area.Dx(), area.Dy()), l.cross(area.Dx(), area.Dy())
+	lines := l.lines(l.items(children, areaCross), areaMain)
+	if len(lines) == 1 {
+		lines[0].cross = areaCross // A single line fills the box
+	}
+
+	crossPos := 0
+	for _, line := range lines {
+		free := l.flexLine(line, areaMain)
+		offset, spacing := l.justify(free, len(line.items))
+
+		pos := 0.0
+		for i, it := range line.items {
+			l.alignItem(it, line.cross)
+			crossOffset := it.crossStart
+			switch l.Align {
+			case AlignItemsEnd:
+				crossOffset = line.cross - it.finalCross - it.crossEnd
+			case AlignItemsCenter:
+				crossOffset = it.crossStart + (line.cross-it.outerCrossFinal())/2
+			}
+
+			mainStart := int(math.Round(offset+pos)) + it.marginStart
+			x, y := l.point(mainStart, crossPos+crossOffset)
+			w, h := l.wh(it.finalMain, it.finalCross)
+			origin := area.Min.Add(image.Pt(x, y))
+			gui.ArrangeElement(it.el, image.Rectangle{Min: origin, Max: origin.Add(image.Pt(w, h))})
+
+			pos += float64(it.finalMain + it.marginStart + it.marginEnd)
+			if i < len(line.items)-1 {
+				pos += float64(l.Gap) + spacing
+			}
+		}
+		crossPos += line.cross + l.Gap
+	}
+}
+
+// items measures the children with the room the box offers
+func (l *BoxLayout) items(children []gui.GUIElement, crossMax int) []*boxItem {
+	items := make([]*boxItem, 0, len(children))
+	for _, child := range children {
+		m := gui.MarginOf(child)
+		it := &boxItem{el: child, flex: l.FlexOf(child), maxMain: gui.Unbounded, maxCross: gui.Unbounded}
+		if l.Direction == BoxRow {
+			it.marginStart, it.marginEnd, it.crossStart, it.crossEnd = m.Left, m.Right, m.Top, m.Bottom
+		} else {
+			it.marginStart, it.marginEnd, it.crossStart, it.crossEnd = m.Top, m.Bottom, m.Left, m.Right
+		}
+
+		// Children ask for their natural main size; overflow is shrunk later
+		crossRoom := available(crossMax, it.crossStart+it.crossEnd)
+		room := l.constraints(0, gui.Unbounded, 0, crossRoom)
+		if it.flex.Basis >= 0 {
+			room = l.constraints(it.flex.Basis, it.flex.Basis, 0, crossRoom)
+		}
+		size := gui.MeasureElement(child, room)
+		it.base, it.cross = l.main(size.Width, size.Height), l.cross(size.Width, size.Height)
+
+		if sizer, ok := child.(interface{ MinSize() (int, int) }); ok {
+			it.minMain = l.main(sizer.MinSize())
+		}
+		if sizer, ok := child.(interface{ MaxSize() (int, int) }); ok {
+			if max := l.main(sizer.MaxSize()); max > 0 {
+				it.maxMain = max
+			}
+			if max := l.cross(sizer.MaxSize()); max > 0 {
+				it.maxCross = max
+			}
+		}
+		items = append(items, it)
+	}
+	return items
+}
+
+// lines splits items into lines no longer than mainMax when wrapping
+func (l *BoxLayout) lines(items []*boxItem, mainMax int) []*boxLine {
+	lines := []*boxLine{{}}
+	used := 0
+	for _, it := range items {
+		line := lines[len(lines)-1]
+		if l.Wrap && len(line.items) > 0 && used+l.Gap+it.outerMain() > mainMax {
+			line = &boxLine{}
+			lines = append(lines, line)
+			used = 0
+		}
+		if len(line.items) > 0 {
+			used += l.Gap
+		}
+		used += it.outerMain()
+		line.items = append(line.items, it)
+		if c := it.outerCross(); c > line.cross {
+			line.cross = c
+		}
+	}
+	return lines
+}
+
+// flexLine grows or shrinks the items of a line to fill mainSize and
+// returns the space left for justifying
+func (l *BoxLayout) flexLine(line *boxLine, mainSize int) int {
+	frozen := make([]bool, len(line.items))
+	for i, it := range line.items {
+		it.finalMain = it.base
+		// Items that cannot grow or shrink keep their base size
+		frozen[i] = it.flex.Grow == 0 && it.flex.Shrink == 0
+	}
+
+	// Share out free space by grow weight, or overflow by shrink weight
+	// scaled by size so small children do not vanish first. Items hitting
+	// their minimum or maximum are frozen there and the rest shared again.
+	for {
+		free := l.freeSpace(line, mainSize, frozen)
+		total := 0.0
+		for i, it := range line.items {
+			if !frozen[i] {
+				total += l.flexWeight(it, free)
+			}
+		}
+		if free == 0 || total == 0 {
+			break
+		}
+
+		share := 0.0
+		clamped := false
+		for i, it := range line.items {
+			if frozen[i] {
+				continue
+			}
+			before := math.Round(share)
+			share += float64(free) * l.flexWeight(it, free) / total
+			it.finalMain = it.base + int(math.Round(share)-before)
+
+			if limited := clampMain(it); limited != it.finalMain {
+				it.finalMain = limited
+				frozen[i] = true
+				clamped = true
+			}
+		}
+		if !clamped {
+			break
+		}
+		// Unfrozen items start over from their base sizes
+		for i, it := range line.items {
+			if !frozen[i] {
+				it.finalMain = it.base
+			}
+		}
+	}
+
+	for _, it := range line.items {
+		it.finalMain = clampMain(it)
+	}
+	return l.freeSpace(line, mainSize, nil)
+}
+
+// freeSpace returns the main axis space left on a line, counting frozen
+// items at their final size and the rest at their base size
+func (l *BoxLayout) freeSpace(line *boxLine, mainSize int, frozen []bool) int {
+	free := mainSize - l.Gap*(len(line.items)-1)
+	for i, it := range line.items {
+		size := it.finalMain
+		if frozen != nil && !frozen[i] {
+			size = it.base
+		}
+		free -= size + it.marginStart + it.marginEnd
+	}
+	return free
+}
+
+// flexWeight returns an item's share of free space, or of overflow when
+// free is negative
+func (l *BoxLayout) flexWeight(it *boxItem, free int) float64 {
+	if free < 0 {
+		return it.flex.Shrink * float64(it.base)
+	}
+	return it.flex.Grow
+}
+
+// clampMain keeps an item's main size within its limits
+func clampMain(it *boxItem) int {
+	size := it.finalMain
+	if size > it.maxMain {
+		size = it.maxMain
+	}
+	if size < it.minMain {
+		size = it.minMain
+	}
+	if size < 0 {
+		size = 0
+	}
+	return size
+}
+
+// alignItem sets an item's cross size, stretching it across the line or
+// measuring it again now its main size is known
+func (l *BoxLayout) alignItem(it *boxItem, lineCross int) {
+	room := available(lineCross, it.crossStart+it.crossEnd)
+	if l.Align == AlignItemsStretch {
+		if room > it.maxCross {
+			room = it.maxCross
+		}
+		size := gui.MeasureElement(it.el, l.constraints(it.finalMain, it.finalMain, room, room))
+		it.finalCross = l.cross(size.Width, size.Height)
+		return
+	}
+	size := gui.MeasureElement(it.el, l.constraints(it.finalMain, it.finalMain, 0, room))
+	it.finalCross = l.cross(size.Width, size.Height)
+}
+
+// justify returns where the first item starts and the extra space
+// between items for the box's JustifyContent
+func (l *BoxLayout) justify(free, count int) (offset, spacing float64) {
+	if free <= 0 || count == 0 {
+		return 0, 0
+	}
+	f := float64(free)
+	switch l.Justify {
+	case JustifyEnd:
+		return f, 0
+	case JustifyCenter:
+		return f / 2, 0
+	case JustifySpaceBetween:
+		if count == 1 {
+			return 0, 0
+		}
+		return 0, f / float64(count-1)
+	case JustifySpaceAround:
+		return f / float64(2*count), f / float64(count)
+	case JustifySpaceEvenly:
+		return f / float64(count+1), f / float64(count+1)
+	}
+	return 0, 0
+}
+
+// main returns the main axis component of a width and height
+func (l *BoxLayout) main(width, height int) int {
+	if l.Direction == BoxRow {
+		return width
+	}
+	return height
+}
+
+// cross returns the cross axis component of a width and height
+func (l *BoxLayout) cross(width, height int) int {
+	if l.Direction == BoxRow {
+		return height
+	}
+	return width
+}
+
+// size builds a size from main and cross components
+func (l *BoxLayout) size(main, cross int) gui.Size {
+	w, h := l.wh(main, cross)
+	return gui.Size{Width: w, Height: h}
+}
+
+// wh orders main and cross components as width and height
+func (l *BoxLayout) wh(main, cross int) (int, int) {
+	if l.Direction == BoxRow {
+		return main, cross
+	}
+	return cross, main
+}
+
+// point orders main and cross positions as x and y
+func (l *BoxLayout) point(main, cross int) (int, int) {
+	return l.wh(main, cross)
+}
+
+// constraints builds constraints from main and cross limits
+func (l *BoxLayout) constraints(minMain, maxMain, minCross, maxCross int) gui.Constraints {
+	minW, minH := l.wh(minMain, minCross)
+	maxW, maxH := l.wh(maxMain, maxCross)
+	return gui.Constraints{MinWidth: minW, MinHeight: minH, MaxWidth: maxW, MaxHeight: maxH}
+}
+
+// available subtracts margins from a limit, leaving Unbounded alone
+func available(limit, margins int) int {
+	if limit >= gui.Unbounded {
+		return gui.Unbounded
+	}
+	if limit < margins {
+		return 0
+	}
+	return limit - margins
+}
+
+// Box is a container laying out its children with a BoxLayout
+type Box struct {
+	*gui.Element
+	layout *BoxLayout
+}
+
+// NewBox creates an empty box in the given direction
+func NewBox(direction BoxDirection) *Box {
+	box := &Box{
+		Element: gui.NewElement(0, 0, 0, 0),
+		layout:  NewBoxLayout(direction),
+	}
+	box.SetLayout(box.layout)
+	return box
+}
+
+// NewRow creates a box laying its children out left to right
+func NewRow(children ...gui.GUIElement) *Box {
+	return NewBox(BoxRow).Add(children...)
+}
+
+// NewColumn creates a box laying its children out top to bottom
+func NewColumn(children ...gui.GUIElement) *Box {
+	return NewBox(BoxColumn).Add(children...)
+}
+
+// Add appends children to the box
+func (b *Box) Add(children ...gui.GUIElement) *Box {
+	for _, child := range children {
+		b.AddChild(child)
+	}
+	return b
+}
+
+// AddFlex appends a child with its flex settings
+func (b *Box) AddFlex(child gui.GUIElement, flex Flex) *Box {
+	b.layout.SetFlex(child, flex)
+	b.AddChild(child)
+	return b
+}
+
+// SetDirection sets whether children run in a row or a column
+func (b *Box) SetDirection(direction BoxDirection) *Box {
+	b.layout.Direction = direction
+	b.InvalidateLayout()
+	return b
+}
+
+// SetWrap lets children that do not fit continue on a new line
+func (b *Box) SetWrap(wrap bool) *Box {
+	b.layout.Wrap = wrap
+	b.InvalidateLayout()
+	return b
+}
+
+// SetGap sets the space between children and between wrapped lines
+func (b *Box) SetGap(gap int) *Box {
+	b.layout.Gap = gap
+	b.InvalidateLayout()
+	return b
+}
+
+// SetJustify sets how free space along the main axis is shared
+func (b *Box) SetJustify(justify JustifyContent) *Box {
+	b.layout.Justify = justify
+	b.InvalidateLayout()
+	return b
+}
+
+// SetAlign sets how children are placed across the main axis
+func (b *Box) SetAlign(align AlignItems) *Box {
+	b.layout.Align = align
+	b.InvalidateLayout()
+	return b
+}
+
+// SetFlex sets how a child grows and shrinks
+func (b *Box) SetFlex(child gui.GUIElement, flex Flex) *Box {
+	b.layout.SetFlex(child, flex)
+	b.InvalidateLayout()
+	return b
+}
+
+// FlexOf returns a child's flex settings
+func (b *Box) FlexOf(child gui.GUIElement) Flex {
+	return b.layout.FlexOf(child)
+}
@@ -0,0 +1,320 @@
+package components
+
+import (
+	"image"
+	"testing"
+
+	"github.com/opd-ai/gui"
+)
+
+// boxChild describes a child of a test box
+type boxChild struct {
+	width, height int
+	flex          *Flex // nil keeps DefaultFlex
+	minWidth      int
+	margin        gui.Insets
+	hidden        bool
+}
+
+// rect builds a rectangle from a position and size
+func rect(x, y, width, height int) image.Rectangle {
+	return image.Rect(x, y, x+width, y+height)
+}
+
+func TestBoxLayoutArrange(t *testing.T) {
+	tests := []struct {
+		name          string
+		direction     BoxDirection
+		width, height int
+		wrap          bool
+		gap           int
+		justify       JustifyContent
+		align         AlignItems
+		children      []boxChild
+		want          []image.Rectangle // Bounds per child; hidden children are not checked
+	}{
+		{
+			name: "justify start", width: 300, height: 100, align: AlignItemsStart,
+			children: []boxChild{{width: 50, height: 20}, {width: 70, height: 30}},
+			want:     []image.Rectangle{rect(0, 0, 50, 20), rect(50, 0, 70, 30)},
+		},
+		{
+			name: "justify end", width: 300, height: 100, justify: JustifyEnd, align: AlignItemsStart,
+			children: []boxChild{{width: 50, height: 20}, {width: 70, height: 30}},
+			want:     []image.Rectangle{rect(180, 0, 50, 20), rect(230, 0, 70, 30)},
+		},
+		{
+			name: "justify center", width: 300, height: 100, justify: JustifyCenter, align: AlignItemsStart,
+			children: []boxChild{{width: 50, height: 20}, {width: 70, height: 30}},
+			want:     []image.Rectangle{rect(90, 0, 50, 20), rect(140, 0, 70, 30)},
+		},
+		{
+			name: "justify space between", width: 300, height: 100, justify: JustifySpaceBetween, align: AlignItemsStart,
+			children: []boxChild{{width: 50, height: 20}, {width: 70, height: 30}},
+			want:     []image.Rectangle{rect(0, 0, 50, 20), rect(230, 0, 70, 30)},
+		},
+		{
+			name: "justify space around", width: 300, height: 100, justify: JustifySpaceAround, align: AlignItemsStart,
+			children: []boxChild{{width: 50, height: 20}, {width: 70, height: 30}},
+			want:     []image.Rectangle{rect(45, 0, 50, 20), rect(185, 0, 70, 30)},
+		},
+		{
+			name: "justify space evenly", width: 300, height: 100, justify: JustifySpaceEvenly, align: AlignItemsStart,
+			children: []boxChild{{width: 50, height: 20}, {width: 70, height: 30}},
+			want:     []image.Rectangle{rect(60, 0, 50, 20), rect(170, 0, 70, 30)},
+		},
+		{
+			name: "justify space between with one child", width: 300, height: 100, justify: JustifySpaceBetween, align: AlignItemsStart,
+			children: []boxChild{{width: 50, height: 20}},
+			want:     []image.Rectangle{rect(0, 0, 50, 20)},
+		},
+		{
+			name: "align stretch", width: 300, height: 100, align: AlignItemsStretch,
+			children: []boxChild{{width: 50, height: 20}, {width: 70, height: 30}},
+			want:     []image.Rectangle{rect(0, 0, 50, 100), rect(50, 0, 70, 100)},
+		},
+		{
+			name: "align end", width: 300, height: 100, align: AlignItemsEnd,
+			children: []boxChild{{width: 50, height: 20}, {width: 70, height: 30}},
+			want:     []image.Rectangle{rect(0, 80, 50, 20), rect(50, 70, 70, 30)},
+		},
+		{
+			name: "align center", width: 300, height: 100, align: AlignItemsCenter,
+			children: []boxChild{{width: 50, height: 20}, {width: 70, height: 30}},
+			want:     []image.Rectangle{rect(0, 40, 50, 20), rect(50, 35, 70, 30)},
+		},
+		{
+			name: "gap", width: 300, height: 100, gap: 10, align: AlignItemsStart,
+			children: []boxChild{{width: 50, height: 20}, {width: 70, height: 30}, {width: 30, height: 10}},
+			want:     []image.Rectangle{rect(0, 0, 50, 20), rect(60, 0, 70, 30), rect(140, 0, 30, 10)},
+		},
+		{
+			name: "gap with space between", width: 300, height: 100, gap: 10, justify: JustifySpaceBetween, align: AlignItemsStart,
+			children: []boxChild{{width: 50, height: 20}, {width: 70, height: 30}},
+			want:     []image.Rectangle{rect(0, 0, 50, 20), rect(230, 0, 70, 30)},
+		},
+		{
+			name: "grow by weight", width: 300, height: 100, align: AlignItemsStart,
+			children: []boxChild{
+				{width: 50, height: 20, flex: &Flex{Grow: 1, Shrink: 1, Basis: BasisAuto}},
+				{width: 70, height: 30, flex: &Flex{Grow: 2, Shrink: 1, Basis: BasisAuto}},
+			},
+			want: []image.Rectangle{rect(0, 0, 110, 20), rect(110, 0, 190, 30)},
+		},
+		{
+			name: "grow leaves rigid children alone", width: 300, height: 100, gap: 10, align: AlignItemsStart,
+			children: []boxChild{
+				{width: 50, height: 20},
+				{width: 70, height: 30, flex: &Flex{Grow: 1, Shrink: 1, Basis: BasisAuto}},
+			},
+			want: []image.Rectangle{rect(0, 0, 50, 20), rect(60, 0, 240, 30)},
+		},
+		{
+			name: "shrink by size", width: 150, height: 100, align: AlignItemsStart,
+			children: []boxChild{{width: 100, height: 20}, {width: 200, height: 20}},
+			want:     []image.Rectangle{rect(0, 0, 50, 20), rect(50, 0, 100, 20)},
+		},
+		{
+			name: "shrink clamped to minimum", width: 100, height: 100, align: AlignItemsStart,
+			children: []boxChild{{width: 100, height: 20, minWidth: 80}, {width: 100, height: 20}},
+			want:     []image.Rectangle{rect(0, 0, 80, 20), rect(80, 0, 20, 20)},
+		},
+		{
+			name: "no shrink overflows", width: 100, height: 100, align: AlignItemsStart,
+			children: []boxChild{
+				{width: 80, height: 20, flex: &Flex{Basis: BasisAuto}},
+				{width: 80, height: 20, flex: &Flex{Basis: BasisAuto}},
+			},
+			want: []image.Rectangle{rect(0, 0, 80, 20), rect(80, 0, 80, 20)},
+		},
+		{
+			name: "fixed basis overrides measured size", width: 300, height: 100, align: AlignItemsStart,
+			children: []boxChild{
+				{width: 50, height: 20, flex: &Flex{Basis: 30}},
+				{width: 50, height: 20},
+			},
+			want: []image.Rectangle{rect(0, 0, 30, 20), rect(30, 0, 50, 20)},
+		},
+		{
+			name: "zero basis grows from nothing, auto from measured size", width: 300, height: 100, align: AlignItemsStart,
+			children: []boxChild{
+				{width: 50, height: 20, flex: &Flex{Grow: 1, Basis: 0}},
+				{width: 100, height: 20, flex: &Flex{Grow: 1, Basis: BasisAuto}},
+			},
+			want: []image.Rectangle{rect(0, 0, 100, 20), rect(100, 0, 200, 20)},
+		},
+		{
+			name: "wrap into several lines", width: 130, height: 200, wrap: true, gap: 10, align: AlignItemsStart,
+			children: []boxChild{
+				{width: 60, height: 20}, {width: 60, height: 30},
+				{width: 60, height: 20}, {width: 60, height: 20},
+				{width: 60, height: 20},
+			},
+			want: []image.Rectangle{
+				rect(0, 0, 60, 20), rect(70, 0, 60, 30),
+				rect(0, 40, 60, 20), rect(70, 40, 60, 20),
+				rect(0, 70, 60, 20),
+			},
+		},
+		{
+			name: "wrapped lines justify separately", width: 130, height: 200, wrap: true, gap: 10, justify: JustifyEnd, align: AlignItemsStart,
+			children: []boxChild{{width: 60, height: 20}, {width: 60, height: 20}, {width: 40, height: 20}},
+			want:     []image.Rectangle{rect(0, 0, 60, 20), rect(70, 0, 60, 20), rect(90, 30, 40, 20)},
+		},
+		{
+			name: "wrapped lines stretch to their own height", width: 130, height: 200, wrap: true, align: AlignItemsStretch,
+			children: []boxChild{{width: 60, height: 20}, {width: 60, height: 30}, {width: 60, height: 10}},
+			want:     []image.Rectangle{rect(0, 0, 60, 30), rect(60, 0, 60, 30), rect(0, 30, 60, 10)},
+		},
+		{
+			name: "margins", width: 300, height: 100, align: AlignItemsStart,
+			children: []boxChild{
+				{width: 50, height: 20, margin: gui.Insets{Top: 3, Left: 5, Right: 10}},
+				{width: 50, height: 20, margin: gui.Insets{Left: 2}},
+			},
+			want: []image.Rectangle{rect(5, 3, 50, 20), rect(67, 0, 50, 20)},
+		},
+		{
+			name: "margins with align end", width: 300, height: 100, align: AlignItemsEnd,
+			children: []boxChild{{width: 50, height: 20, margin: gui.Insets{Bottom: 5}}},
+			want:     []image.Rectangle{rect(0, 75, 50, 20)},
+		},
+		{
+			name: "margins with stretch", width: 300, height: 100, align: AlignItemsStretch,
+			children: []boxChild{{width: 50, height: 20, margin: gui.Insets{Top: 10, Bottom: 5}}},
+			want:     []image.Rectangle{rect(0, 10, 50, 85)},
+		},
+		{
+			name: "hidden children take no space", width: 300, height: 100, gap: 10, align: AlignItemsStart,
+			children: []boxChild{
+				{width: 50, height: 20, hidden: true},
+				{width: 70, height: 30},
+				{width: 40, height: 20, hidden: true},
+				{width: 30, height: 10},
+			},
+			want: []image.Rectangle{{}, rect(0, 0, 70, 30), {}, rect(80, 0, 30, 10)},
+		},
+		{
+			name: "column", direction: BoxColumn, width: 100, height: 300, gap: 5, justify: JustifyCenter, align: AlignItemsStart,
+			children: []boxChild{{width: 50, height: 20}, {width: 70, height: 30}},
+			want:     []image.Rectangle{rect(0, 123, 50, 20), rect(0, 148, 70, 30)},
+		},
+		{
+			name: "column grows and stretches", direction: BoxColumn, width: 100, height: 300, align: AlignItemsStretch,
+			children: []boxChild{
+				{width: 50, height: 20},
+				{width: 70, height: 30, flex: &Flex{Grow: 1, Shrink: 1, Basis: BasisAuto}},
+			},
+			want: []image.Rectangle{rect(0, 0, 100, 20), rect(0, 20, 100, 280)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			box := NewBox(tt.direction).SetWrap(tt.wrap).SetGap(tt.gap).SetJustify(tt.justify).SetAlign(tt.align)
+			box.SetSize(tt.width, tt.height)
+
+			children := make([]*gui.Element, len(tt.children))
+			for i, c := range tt.children {
+				child := gui.NewElement(0, 0, c.width, c.height)
+				child.SetMinSize(c.minWidth, 0)
+				child.SetMargin(c.margin)
+				child.SetVisible(!c.hidden)
+				box.AddChild(child)
+				if c.flex != nil {
+					box.SetFlex(child, *c.flex)
+				}
+				children[i] = child
+			}
+			box.UpdateLayout()
+
+			for i, child := range children {
+				if tt.children[i].hidden {
+					continue
+				}
+				x, y, width, height := child.GetBounds()
+				if got := rect(x, y, width, height); got != tt.want[i] {
+					t.Errorf("child %d: bounds %v, want %v", i, got, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestBoxLayoutMeasure(t *testing.T) {
+	tests := []struct {
+		name        string
+		direction   BoxDirection
+		wrap        bool
+		gap         int
+		constraints gui.Constraints
+		children    []boxChild
+		want        gui.Size
+	}{
+		{
+			name: "row", constraints: gui.UnboundedConstraints(),
+			children: []boxChild{{width: 50, height: 20}, {width: 70, height: 30}},
+			want:     gui.Size{Width: 120, Height: 30},
+		},
+		{
+			name: "column with gap", direction: BoxColumn, gap: 10, constraints: gui.UnboundedConstraints(),
+			children: []boxChild{{width: 50, height: 20}, {width: 70, height: 30}},
+			want:     gui.Size{Width: 70, Height: 60},
+		},
+		{
+			name: "margins", constraints: gui.UnboundedConstraints(),
+			children: []boxChild{
+				{width: 50, height: 20, margin: gui.Insets{Top: 3, Left: 5, Right: 10}},
+				{width: 50, height: 20, margin: gui.Insets{Left: 2}},
+			},
+			want: gui.Size{Width: 117, Height: 23},
+		},
+		{
+			name: "fixed basis", constraints: gui.UnboundedConstraints(),
+			children: []boxChild{{width: 50, height: 20, flex: &Flex{Basis: 30}}, {width: 50, height: 20}},
+			want:     gui.Size{Width: 80, Height: 20},
+		},
+		{
+			name: "hidden children", gap: 10, constraints: gui.UnboundedConstraints(),
+			children: []boxChild{{width: 50, height: 20}, {width: 200, height: 90, hidden: true}, {width: 70, height: 30}},
+			want:     gui.Size{Width: 130, Height: 30},
+		},
+		{
+			name: "wrap into several lines", wrap: true, gap: 10, constraints: gui.LooseConstraints(130, gui.Unbounded),
+			children: []boxChild{
+				{width: 60, height: 20}, {width: 60, height: 30},
+				{width: 60, height: 20}, {width: 60, height: 20},
+				{width: 60, height: 20},
+			},
+			want: gui.Size{Width: 130, Height: 90},
+		},
+		{
+			name: "no wrap overflows the constraints", constraints: gui.LooseConstraints(100, 100),
+			children: []boxChild{{width: 60, height: 20}, {width: 60, height: 20}},
+			want:     gui.Size{Width: 100, Height: 20},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			layout := NewBoxLayout(tt.direction)
+			layout.Wrap, layout.Gap = tt.wrap, tt.gap
+			box := gui.NewElement(0, 0, 0, 0)
+			box.SetLayout(layout)
+
+			for _, c := range tt.children {
+				child := gui.NewElement(0, 0, c.width, c.height)
+				child.SetMargin(c.margin)
+				child.SetVisible(!c.hidden)
+				box.AddChild(child)
+				if c.flex != nil {
+					layout.SetFlex(child, *c.flex)
+				}
+			}
+
+			if got := box.Measure(tt.constraints); got != tt.want {
+				t.Errorf("Measure = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -2,8 +2,10 @@ package components
 
 import (
 	"image"
+	"time"
 
 	"github.com/opd-ai/gui"
+	"github.com/opd-ai/gui/animation"
 	"github.com/lucasb-eyer/go-colorful"
 	"golang.org/x/image/font"
 	"golang.org/x/image/font/basicfont"
@@ -19,6 +21,9 @@ const (
 	ButtonStateDisabled
 )
 
+// DefaultButtonFade is how long background colour changes between states take
+const DefaultButtonFade = 120 * time.Millisecond
+
 // Button represents a clickable button component
 type Button struct {
 	*gui.Element
@@ -27,6 +32,9 @@ type Button struct {
 	font    font.Face
 	state   ButtonState
 	enabled bool
+	pressed bool // Left button or Space went down on us and has not been released
+	focused bool
+	hovered bool // Pointer is over the button, tracked by the window's enter/leave events
 
 	// Colors for different states
 	normalBgColor   colorful.Color
@@ -34,9 +42,15 @@ type Button struct {
 	pressedBgColor  colorful.Color
 	disabledBgColor colorful.Color
 
+	// Displayed background, faded towards the current state's colour
+	bgColor      colorful.Color
+	fade         *animation.Tween
+	fadeDuration time.Duration
+
 	textColor         colorful.Color
 	disabledTextColor colorful.Color
 	borderColor       colorful.Color
+	focusColor        colorful.Color
 
 	// Event callbacks
 	onClick   func()
@@ -66,14 +80,26 @@ func NewButton(text string) *Button {
 		textColor:         colorful.Color{R: 0, G: 0, B: 0},       // Black
 		disabledTextColor: colorful.Color{R: 0.6, G: 0.6, B: 0.6}, // Gray
 		borderColor:       colorful.Color{R: 0.6, G: 0.6, B: 0.6}, // Gray
+		focusColor:        colorful.Color{R: 0.2, G: 0.4, B: 0.9}, // Blue
 
 		borderWidth:  1,
 		cornerRadius: 3,
+		fadeDuration: DefaultButtonFade,
 	}
+	button.bgColor = button.normalBgColor
+	button.SetFocusable(true)
 
 	// Register event handlers
 	button.AddEventHandler(gui.EventTypeClick, gui.EventHandlerFunc(button.handleClick))
-	button.AddEventHandler(gui.EventTypeMouseMove, gui.EventHandlerFunc(button.handleMouseMove))
+	button.AddEventHandler(gui.EventTypeMouseDown, gui.EventHandlerFunc(button.handleMouseDown))
+	button.AddEventHandler(gui.EventTypeMouseUp, gui.EventHandlerFunc(button.handleMouseUp))
+	button.AddEventHandler(gui.EventTypeMouseDrag, gui.EventHandlerFunc(button.handleMouseDrag))
+	button.AddEventHandler(gui.EventTypeMouseEnter, gui.EventHandlerFunc(button.handleMouseEnter))
+	button.AddEventHandler(gui.EventTypeMouseLeave, gui.EventHandlerFunc(button.handleMouseLeave))
+	button.AddEventHandler(gui.EventTypeKeyPress, gui.EventHandlerFunc(button.handleKeyPress))
+	button.AddEventHandler(gui.EventTypeKeyRelease, gui.EventHandlerFunc(button.handleKeyRelease))
+	button.AddEventHandler(gui.EventTypeFocus, gui.EventHandlerFunc(button.handleFocus))
+	button.AddEventHandler(gui.EventTypeBlur, gui.EventHandlerFunc(button.handleBlur))
 
 	return button
 }
@@ -81,6 +107,8 @@ func NewButton(text string) *Button {
 // SetText updates the button's text
 func (b *Button) SetText(text string) *Button {
 	b.text = text
+	b.Invalidate()
+	b.InvalidateLayout()
 	return b
 }
 
@@ -92,12 +120,16 @@ func (b *Button) GetText() string {
 // SetIcon sets an icon image for the button
 func (b *Button) SetIcon(icon image.Image) *Button {
 	b.icon = icon
+	b.Invalidate()
+	b.InvalidateLayout()
 	return b
 }
 
 // SetFont updates the button's font
 func (b *Button) SetFont(font font.Face) *Button {
 	b.font = font
+	b.Invalidate()
+	b.InvalidateLayout()
 	return b
 }
 
@@ -105,13 +137,25 @@ func (b *Button) SetFont(font font.Face) *Button {
 func (b *Button) SetEnabled(enabled bool) *Button {
 	b.enabled = enabled
 	if !enabled {
-		b.state = ButtonStateDisabled
+		b.setState(ButtonStateDisabled)
 	} else if b.state == ButtonStateDisabled {
-		b.state = ButtonStateNormal
+		b.setState(b.restingState())
 	}
+	b.Invalidate()
 	return b
 }
 
+// SetFadeDuration sets how long state colour changes take; zero switches instantly
+func (b *Button) SetFadeDuration(duration time.Duration) *Button {
+	b.fadeDuration = duration
+	return b
+}
+
+// GetState returns the button's current visual state
+func (b *Button) GetState() ButtonState {
+	return b.state
+}
+
 // IsEnabled returns whether the button is enabled
 func (b *Button) IsEnabled() bool {
 	return b.enabled
@@ -120,30 +164,42 @@ func (b *Button) IsEnabled() bool {
 // SetNormalColor sets the normal background color
 func (b *Button) SetNormalColor(color colorful.Color) *Button {
 	b.normalBgColor = color
+	b.snapBackground()
 	return b
 }
 
 // SetHoverColor sets the hover background color
 func (b *Button) SetHoverColor(color colorful.Color) *Button {
 	b.hoverBgColor = color
+	b.snapBackground()
 	return b
 }
 
 // SetPressedColor sets the pressed background color
 func (b *Button) SetPressedColor(color colorful.Color) *Button {
 	b.pressedBgColor = color
+	b.snapBackground()
 	return b
 }
 
 // SetTextColor sets the text color
 func (b *Button) SetTextColor(color colorful.Color) *Button {
 	b.textColor = color
+	b.Invalidate()
 	return b
 }
 
 // SetBorderColor sets the border color
 func (b *Button) SetBorderColor(color colorful.Color) *Button {
 	b.borderColor = color
+	b.Invalidate()
+	return b
+}
+
+// SetFocusColor sets the color of the focus ring
+func (b *Button) SetFocusColor(color colorful.Color) *Button {
+	b.focusColor = color
+	b.Invalidate()
 	return b
 }
 
@@ -167,7 +223,7 @@ func (b *Button) SetOnUnhover(callback func()) *Button {
 
 // handleClick processes mouse click events
 func (b *Button) handleClick(event gui.Event) bool {
-	if !b.enabled {
+	if !b.enabled || event.IsDefaultPrevented() {
 		return false
 	}
 
@@ -177,51 +233,212 @@ func (b *Button) handleClick(event gui.Event) bool {
 		return false
 	}
 
-	// Visual feedback
-	b.state = ButtonStatePressed
-
-	// Execute callback
+	// Execute callback; the pressed look is driven by mouse down and up
 	if b.onClick != nil {
 		b.onClick()
 	}
 
-	// Reset state after a brief moment (in a real implementation,
-	// this would be handled by the event loop timing)
-	b.state = ButtonStateNormal
+	return true
+}
+
+// handleMouseDown shows the pressed state while the left button is held
+func (b *Button) handleMouseDown(event gui.Event) bool {
+	if !b.enabled {
+		return false
+	}
+
+	downEvent := event.(*gui.MouseDownEvent)
+	if downEvent.Button != gui.MouseButtonLeft || !b.ContainsPoint(downEvent.X, downEvent.Y) {
+		return false
+	}
 
+	b.pressed = true
+	b.setState(ButtonStatePressed)
 	return true
 }
 
-// handleMouseMove processes mouse movement for hover effects
-func (b *Button) handleMouseMove(event gui.Event) bool {
+// handleMouseUp ends the press, leaving the hover state if the pointer is still over us
+func (b *Button) handleMouseUp(event gui.Event) bool {
+	if !b.pressed {
+		return false
+	}
+
+	upEvent := event.(*gui.MouseUpEvent)
+	b.pressed = false
+
 	if !b.enabled {
+		return true
+	}
+	if b.ContainsPoint(upEvent.X, upEvent.Y) {
+		b.setState(ButtonStateHover)
+	} else {
+		b.setState(ButtonStateNormal)
+	}
+	return true
+}
+
+// handleMouseDrag releases the pressed look while the pointer is dragged off the button
+func (b *Button) handleMouseDrag(event gui.Event) bool {
+	if !b.pressed || !b.enabled {
 		return false
 	}
 
-	moveEvent := event.(*gui.MouseMoveEvent)
-	wasHover := (b.state == ButtonStateHover)
-	isHover := b.ContainsPoint(moveEvent.X, moveEvent.Y)
+	dragEvent := event.(*gui.MouseDragEvent)
+	if b.ContainsPoint(dragEvent.X, dragEvent.Y) {
+		b.setState(ButtonStatePressed)
+	} else {
+		b.setState(ButtonStateNormal)
+	}
+	return true
+}
+
+// handleKeyPress activates a focused button with Enter, and presses it with Space
+func (b *Button) handleKeyPress(event gui.Event) bool {
+	if !b.enabled || !b.focused || event.IsDefaultPrevented() {
+		return false
+	}
 
-	if isHover && !wasHover {
-		b.state = ButtonStateHover
-		if b.onHover != nil {
-			b.onHover()
+	keyEvent := event.(*gui.KeyPressEvent)
+	switch keyEvent.Key {
+	case gui.KeyEnter, gui.KeyNumpadEnter:
+		if !keyEvent.Repeat && b.onClick != nil {
+			b.onClick()
 		}
 		return true
-	} else if !isHover && wasHover {
-		b.state = ButtonStateNormal
-		if b.onUnhover != nil {
-			b.onUnhover()
-		}
+	case gui.KeySpace:
+		// Like a mouse press, Space activates on release
+		b.pressed = true
+		b.setState(ButtonStatePressed)
 		return true
 	}
-
 	return false
 }
 
-// getCurrentBackgroundColor returns the appropriate background color for the current state
+// handleKeyRelease activates the button when a Space press ends
+func (b *Button) handleKeyRelease(event gui.Event) bool {
+	keyEvent := event.(*gui.KeyReleaseEvent)
+	if keyEvent.Key != gui.KeySpace || !b.pressed {
+		return false
+	}
+
+	b.pressed = false
+	if !b.enabled {
+		return true
+	}
+	b.setState(b.restingState())
+	if b.onClick != nil {
+		b.onClick()
+	}
+	return true
+}
+
+// handleFocus shows the focus ring
+func (b *Button) handleFocus(event gui.Event) bool {
+	b.focused = true
+	b.Invalidate()
+	return true
+}
+
+// handleBlur hides the focus ring and cancels a pending Space press
+func (b *Button) handleBlur(event gui.Event) bool {
+	b.focused = false
+	if b.pressed {
+		b.pressed = false
+		if b.enabled {
+			b.setState(b.restingState())
+		}
+	}
+	b.Invalidate()
+	return true
+}
+
+// IsFocused returns whether the button has keyboard focus
+func (b *Button) IsFocused() bool {
+	return b.focused
+}
+
+// handleMouseEnter shows the hover state when the pointer moves onto the button
+func (b *Button) handleMouseEnter(event gui.Event) bool {
+	b.hovered = true
+	if !b.enabled || b.pressed {
+		return false
+	}
+
+	b.setState(ButtonStateHover)
+	if b.onHover != nil {
+		b.onHover()
+	}
+	return true
+}
+
+// handleMouseLeave restores the normal state when the pointer moves off the button
+func (b *Button) handleMouseLeave(event gui.Event) bool {
+	b.hovered = false
+	if !b.enabled || b.pressed {
+		return false
+	}
+
+	b.setState(ButtonStateNormal)
+	if b.onUnhover != nil {
+		b.onUnhover()
+	}
+	return true
+}
+
+// restingState returns the state to show when the button is not pressed
+func (b *Button) restingState() ButtonState {
+	if b.hovered {
+		return ButtonStateHover
+	}
+	return ButtonStateNormal
+}
+
+// setState changes the visual state, fading the background to the new state's colour
+func (b *Button) setState(state ButtonState) {
+	if b.state == state {
+		return
+	}
+	b.state = state
+
+	if b.fade != nil {
+		b.fade.Cancel()
+		b.fade = nil
+	}
+
+	target := b.getStateBackgroundColor(state)
+	if b.fadeDuration <= 0 {
+		b.bgColor = target
+		b.Invalidate()
+		return
+	}
+
+	b.fade = animation.Color(b.bgColor, target, b.fadeDuration, animation.EaseOutQuad,
+		func(color colorful.Color) {
+			b.bgColor = color
+			b.Invalidate()
+		})
+	b.Animate(b.fade)
+}
+
+// snapBackground jumps to the current state's colour after a colour setter
+func (b *Button) snapBackground() {
+	if b.fade != nil {
+		b.fade.Cancel()
+		b.fade = nil
+	}
+	b.bgColor = b.getStateBackgroundColor(b.state)
+	b.Invalidate()
+}
+
+// getCurrentBackgroundColor returns the displayed background color, which
+// trails the current state's color while a fade is running
 func (b *Button) getCurrentBackgroundColor() colorful.Color {
-	switch b.state {
+	return b.bgColor
+}
+
+// getStateBackgroundColor returns the background color for a state
+func (b *Button) getStateBackgroundColor(state ButtonState) colorful.Color {
+	switch state {
 	case ButtonStateHover:
 		return b.hoverBgColor
 	case ButtonStatePressed:
@@ -241,6 +458,24 @@ func (b *Button) getCurrentTextColor() colorful.Color {
 	return b.textColor
 }
 
+// MeasureContent returns the size of the text and icon with the button's
+// inner padding around them
+func (b *Button) MeasureContent(constraints gui.Constraints) gui.Size {
+	const padding = 8
+
+	lineHeight := 0
+	if b.font != nil {
+		metrics := b.font.Metrics()
+		lineHeight = (metrics.Ascent + metrics.Descent).Ceil()
+	}
+
+	width := measureTextWidth(b.text, b.font) + 2*padding
+	if b.icon != nil {
+		width += lineHeight + 4 // Icons are as tall as the text, plus spacing
+	}
+	return gui.Size{Width: width, Height: lineHeight + 2*padding}
+}
+
 // Render draws the button component
 func (b *Button) Render(canvas gui.Canvas) error {
 	if !b.IsVisible() {
@@ -262,18 +497,25 @@ func (b *Button) Render(canvas gui.Canvas) error {
 		}
 	}
 
+	// Draw focus ring just inside the border
+	if b.focused && width > 4 && height > 4 {
+		if err := canvas.DrawRectangle(x+2, y+2, width-4, height-4, b.focusColor, false); err != nil {
+			return err
+		}
+	}
+
 	// Calculate content positioning
 	contentX := x
 	contentY := y
 	contentWidth := width
 	contentHeight := height
 
-	// Account for padding
-	padding := 8
-	contentX += padding
-	contentY += padding
-	contentWidth -= 2 * padding
-	contentHeight -= 2 * padding
+	// Account for the element's padding and the inner padding
+	padding := b.Padding()
+	contentX += padding.Left + 8
+	contentY += padding.Top + 8
+	contentWidth -= padding.Horizontal() + 2*8
+	contentHeight -= padding.Vertical() + 2*8
 
 	// Draw icon if present
 	iconWidth := 0
@@ -0,0 +1,552 @@
+package components
+
+import (
+	"fmt"
+	"image"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/opd-ai/gui"
+)
+
+// TrackSizing is how a grid row or column gets its size
+type TrackSizing int
+
+const (
+	TrackFixed    TrackSizing = iota // A size in pixels
+	TrackFraction                    // A share of the space left over
+	TrackAuto                        // The size of the largest content
+)
+
+// Track is one row or column of a grid
+type Track struct {
+	Sizing TrackSizing
+	Value  float64 // Pixels for fixed tracks, the share for fractional ones
+}
+
+// FixedTrack returns a track of a fixed number of pixels
+func FixedTrack(pixels int) Track {
+	return Track{Sizing: TrackFixed, Value: float64(pixels)}
+}
+
+// FrTrack returns a track taking a share of the leftover space
+func FrTrack(fr float64) Track {
+	return Track{Sizing: TrackFraction, Value: fr}
+}
+
+// AutoTrack returns a track sized to fit its content
+func AutoTrack() Track {
+	return Track{Sizing: TrackAuto}
+}
+
+// ParseTracks parses a space separated track list such as "120 1fr auto";
+// plain numbers and a px suffix are fixed sizes
+func ParseTracks(spec string) ([]Track, error) {
+	var tracks []Track
+	for _, field := range strings.Fields(spec) {
+		switch {
+		case field == "auto":
+			tracks = append(tracks, AutoTrack())
+		case strings.HasSuffix(field, "fr"):
+			fr, err := strconv.ParseFloat(strings.TrimSuffix(field, "fr"), 64)
+			if err != nil || fr < 0 {
+				return nil, fmt.Errorf("grid track %q: invalid fraction", field)
+			}
+			tracks = append(tracks, FrTrack(fr))
+		default:
+			px, err := strconv.Atoi(strings.TrimSuffix(field, "px"))
+			if err != nil || px < 0 {
+				return nil, fmt.Errorf("grid track %q: invalid size", field)
+			}
+			tracks = append(tracks, FixedTrack(px))
+		}
+	}
+	return tracks, nil
+}
+
+// GridAuto places a child in the next free cell
+const GridAuto = -1
+
+// GridCell places a child in a grid. Row and Column are zero based, or
+// GridAuto; a named Area overrides them. Spans below one count as one.
+type GridCell struct {
+	Row, Column         int
+	RowSpan, ColumnSpan int
+	Area                string
+	HAlign, VAlign      AlignItems // Placement within the cell, stretched by default
+}
+
+// GridLayout places children in rows and columns of fixed, fractional or
+// content sized tracks. Children placed beyond the tracks given get extra
+// auto tracks.
+type GridLayout struct {
+	Columns   []Track
+	Rows      []Track
+	ColumnGap int
+	RowGap    int
+
+	cells map[gui.GUIElement]GridCell
+	areas map[string]image.Rectangle // Cell ranges, columns on X and rows on Y
+}
+
+// NewGridLayout creates a grid layout with the given columns
+func NewGridLayout(columns ...Track) *GridLayout {
+	return &GridLayout{
+		Columns: columns,
+		cells:   make(map[gui.GUIElement]GridCell),
+		areas:   make(map[string]image.Rectangle),
+	}
+}
+
+// SetCell sets where a child goes and how it is aligned
+func (l *GridLayout) SetCell(child gui.GUIElement, cell GridCell) {
+	l.cells[child] = cell
+}
+
+// CellOf returns a child's placement; unplaced children are auto placed
+func (l *GridLayout) CellOf(child gui.GUIElement) GridCell {
+	if cell, ok := l.cells[child]; ok {
+		return cell
+	}
+	return GridCell{Row: GridAuto, Column: GridAuto}
+}
+
+// ChildRemoved forgets the placement of a child that left the container
+func (l *GridLayout) ChildRemoved(child gui.GUIElement) {
+	delete(l.cells, child)
+}
+
+// SetAreas names regions of the grid with one string per row, each listing
+// a name per column; "." leaves a cell unnamed. Every name must cover a
+// rectangle of cells.
+func (l *GridLayout) SetAreas(rows ...string) error {
+	areas := make(map[string]image.Rectangle)
+	for r, row := range rows {
+		for c, name := range strings.Fields(row) {
+			if name == "." {
+				continue
+			}
+			cell := image.Rect(c, r, c+1, r+1)
+			if area, ok := areas[name]; ok {
+				cell = area.Union(cell)
+			}
+			areas[name] = cell
+		}
+	}
+
+	// A name filling its bounding box once per cell is a rectangle
+	for name, area := range areas {
+		count := 0
+		for r, row := range rows {
+			for c, field := range strings.Fields(row) {
+				if field == name && image.Pt(c, r).In(area) {
+					count++
+				}
+			}
+		}
+		if count != area.Dx()*area.Dy() {
+			return fmt.Errorf("grid area %q is not a rectangle", name)
+		}
+	}
+	l.areas = areas
+	return nil
+}
+
+// gridItem is a child with the cells it covers
+type gridItem struct {
+	el     gui.GUIElement
+	cell   GridCell
+	cells  image.Rectangle // Columns on X and rows on Y
+	margin gui.Insets
+}
+
+// Measure returns the size of the grid with every track at its natural size
+func (l *GridLayout) Measure(children []gui.GUIElement, constraints gui.Constraints) gui.Size {
+	items, columns, rows := l.place(children)
+	widths := l.sizeColumns(items, columns, constraints.MaxWidth)
+	heights := l.sizeRows(items, rows, widths, constraints.MaxHeight)
+	return gui.Size{
+		Width:  sum(widths) + l.ColumnGap*maxInt(len(widths)-1, 0),
+		Height: sum(heights) + l.RowGap*maxInt(len(heights)-1, 0),
+	}
+}
+
+// Arrange sizes the tracks to the area and places each child in its cells
+func (l *GridLayout) Arrange(children []gui.GUIElement, area image.Rectangle) {
+	items, columns, rows := l.place(children)
+	widths := l.sizeColumns(items, columns, area.Dx())
+	heights := l.sizeRows(items, rows, widths, area.Dy())
+	xs := offsets(widths, l.ColumnGap)
+	ys := offsets(heights, l.RowGap)
+
+	for _, it := range items {
+		x0, y0 := xs[it.cells.Min.X], ys[it.cells.Min.Y]
+		x1 := xs[it.cells.Max.X-1] + widths[it.cells.Max.X-1]
+		y1 := ys[it.cells.Max.Y-1] + heights[it.cells.Max.Y-1]
+		cell := image.Rect(x0+it.margin.Left, y0+it.margin.Top, x1-it.margin.Right, y1-it.margin.Bottom)
+		if cell.Dx() < 0 || cell.Dy() < 0 {
+			cell.Max = cell.Min
+		}
+
+		size := gui.MeasureElement(it.el, gui.LooseConstraints(cell.Dx(), cell.Dy()))
+		x, w := alignSpan(it.cell.HAlign, cell.Min.X, cell.Dx(), size.Width)
+		y, h := alignSpan(it.cell.VAlign, cell.Min.Y, cell.Dy(), size.Height)
+		origin := area.Min.Add(image.Pt(x, y))
+		gui.ArrangeElement(it.el, image.Rectangle{Min: origin, Max: origin.Add(image.Pt(w, h))})
+	}
+}
+
+// place resolves every child's cells, auto placing the rest in row-major
+// order, and returns the tracks extended to cover them all
+func (l *GridLayout) place(children []gui.GUIElement) ([]*gridItem, []Track, []Track) {
+	columns := append([]Track(nil), l.Columns...)
+	rows := append([]Track(nil), l.Rows...)
+	if len(columns) == 0 {
+		columns = []Track{AutoTrack()}
+	}
+
+	items := make([]*gridItem, 0, len(children))
+	occupied := make(map[image.Point]bool)
+	var auto []*gridItem
+	for _, child := range children {
+		it := &gridItem{el: child, cell: l.CellOf(child), margin: gui.MarginOf(child)}
+		rowSpan, colSpan := maxInt(it.cell.RowSpan, 1), maxInt(it.cell.ColumnSpan, 1)
+
+		if area, ok := l.areas[it.cell.Area]; ok && it.cell.Area != "" {
+			it.cells = area
+		} else if it.cell.Row >= 0 && it.cell.Column >= 0 {
+			it.cells = image.Rect(it.cell.Column, it.cell.Row, it.cell.Column+colSpan, it.cell.Row+rowSpan)
+		} else {
+			auto = append(auto, it)
+			items = append(items, it)
+			continue
+		}
+		markCells(occupied, it.cells)
+		items = append(items, it)
+	}
+
+	// Auto placed children fill the free cells, keeping within the
+	// explicit columns unless they span more
+	next := image.Point{}
+	for _, it := range auto {
+		rowSpan, colSpan := maxInt(it.cell.RowSpan, 1), maxInt(it.cell.ColumnSpan, 1)
+		width := maxInt(len(columns), colSpan)
+		for {
+			if next.X+colSpan > width {
+				next = image.Pt(0, next.Y+1)
+			}
+			r := image.Rect(next.X, next.Y, next.X+colSpan, next.Y+rowSpan)
+			if !anyCell(occupied, r) {
+				it.cells = r
+				markCells(occupied, r)
+				next.X += colSpan
+				break
+			}
+			next.X++
+		}
+	}
+
+	// Implicit tracks size to their content
+	for _, it := range items {
+		for len(columns) < it.cells.Max.X {
+			columns = append(columns, AutoTrack())
+		}
+		for len(rows) < it.cells.Max.Y {
+			rows = append(rows, AutoTrack())
+		}
+	}
+	return items, columns, rows
+}
+
+// sizeColumns resolves column widths, measuring content without a width limit
+func (l *GridLayout) sizeColumns(items []*gridItem, columns []Track, available int) []int {
+	spans := func(it *gridItem) (int, int) { return it.cells.Min.X, it.cells.Max.X }
+	return sizeTracks(columns, items, spans, func(it *gridItem) int {
+		size := gui.MeasureElement(it.el, gui.UnboundedConstraints())
+		return size.Width + it.margin.Horizontal()
+	}, available, l.ColumnGap)
+}
+
+// sizeRows resolves row heights, measuring content at its column width so
+// wrapped text is as tall as it will be
+func (l *GridLayout) sizeRows(items []*gridItem, rows []Track, widths []int, available int) []int {
+	xs := offsets(widths, l.ColumnGap)
+	spans := func(it *gridItem) (int, int) { return it.cells.Min.Y, it.cells.Max.Y }
+	return sizeTracks(rows, items, spans, func(it *gridItem) int {
+		width := xs[it.cells.Max.X-1] + widths[it.cells.Max.X-1] - xs[it.cells.Min.X] - it.margin.Horizontal()
+		size := gui.MeasureElement(it.el, gui.Constraints{MaxWidth: maxInt(width, 0), MaxHeight: gui.Unbounded})
+		return size.Height + it.margin.Vertical()
+	}, available, l.RowGap)
+}
+
+// sizeTracks sizes one axis: fixed tracks take their size, auto tracks the
+// largest content they hold, and fractional tracks share what is left. With
+// no limit, fractional tracks grow until their content fits, including
+// content spanning them, which they share in proportion to their fr values.
+func sizeTracks(tracks []Track, items []*gridItem, span func(*gridItem) (int, int), measure func(*gridItem) int, available, gap int) []int {
+	sizes := make([]int, len(tracks))
+	content := make([]int, len(tracks)) // Largest single-track content per track
+	for i, t := range tracks {
+		if t.Sizing == TrackFixed {
+			sizes[i] = int(t.Value)
+		}
+	}
+
+	// Content spanning one track sizes it directly
+	var spanning []*gridItem
+	for _, it := range items {
+		start, end := span(it)
+		if end-start > 1 {
+			spanning = append(spanning, it)
+			continue
+		}
+		if m := measure(it); m > content[start] {
+			content[start] = m
+		}
+	}
+	for i, t := range tracks {
+		if t.Sizing == TrackAuto {
+			sizes[i] = content[i]
+		}
+	}
+
+	// Content spanning several tracks grows the auto tracks it covers
+	// evenly when they are too small, unless a fractional track can
+	hasFr := func(start, end int) bool {
+		for i := start; i < end; i++ {
+			if tracks[i].Sizing == TrackFraction {
+				return true
+			}
+		}
+		return false
+	}
+	var frSpanning []*gridItem
+	for _, it := range spanning {
+		start, end := span(it)
+		if hasFr(start, end) {
+			frSpanning = append(frSpanning, it)
+			continue
+		}
+		need := measure(it) - gap*(end-start-1)
+		var autos []int
+		for i := start; i < end; i++ {
+			need -= sizes[i]
+			if tracks[i].Sizing == TrackAuto {
+				autos = append(autos, i)
+			}
+		}
+		for n, i := range autos {
+			if need <= 0 {
+				break
+			}
+			extra := need / (len(autos) - n)
+			sizes[i] += extra
+			need -= extra
+		}
+	}
+
+	// Fractional tracks share the leftover space, or without a limit take
+	// the smallest share that fits all their content
+	totalFr, used := 0.0, gap*maxInt(len(tracks)-1, 0)
+	for i, t := range tracks {
+		if t.Sizing == TrackFraction {
+			totalFr += t.Value
+		} else {
+			used += sizes[i]
+		}
+	}
+	if totalFr == 0 {
+		return sizes
+	}
+
+	unit := 0.0
+	if available >= gui.Unbounded {
+		for i, t := range tracks {
+			if t.Sizing == TrackFraction && t.Value > 0 {
+				unit = math.Max(unit, float64(content[i])/t.Value)
+			}
+		}
+		for _, it := range frSpanning {
+			start, end := span(it)
+			need, fr := measure(it)-gap*(end-start-1), 0.0
+			for i := start; i < end; i++ {
+				if tracks[i].Sizing == TrackFraction {
+					fr += tracks[i].Value
+				} else {
+					need -= sizes[i]
+				}
+			}
+			if fr > 0 {
+				unit = math.Max(unit, float64(need)/fr)
+			}
+		}
+	} else {
+		unit = math.Max(float64(available-used), 0) / totalFr
+	}
+
+	share := 0.0
+	for i, t := range tracks {
+		if t.Sizing != TrackFraction {
+			continue
+		}
+		before := math.Round(share)
+		share += unit * t.Value
+		sizes[i] = int(math.Round(share) - before)
+	}
+	return sizes
+}
+
+// offsets returns where each track starts
+func offsets(sizes []int, gap int) []int {
+	starts := make([]int, len(sizes))
+	pos := 0
+	for i, size := range sizes {
+		starts[i] = pos
+		pos += size + gap
+	}
+	return starts
+}
+
+// alignSpan places content of a size within a span of a cell
+func alignSpan(align AlignItems, start, span, size int) (pos, length int) {
+	if size > span {
+		size = span
+	}
+	switch align {
+	case AlignItemsStart:
+		return start, size
+	case AlignItemsEnd:
+		return start + span - size, size
+	case AlignItemsCenter:
+		return start + (span-size)/2, size
+	}
+	return start, span
+}
+
+func markCells(occupied map[image.Point]bool, r image.Rectangle) {
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			occupied[image.Pt(x, y)] = true
+		}
+	}
+}
+
+func anyCell(occupied map[image.Point]bool, r image.Rectangle) bool {
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			if occupied[image.Pt(x, y)] {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func sum(values []int) int {
+	total := 0
+	for _, v := range values {
+		total += v
+	}
+	return total
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// Grid is a container laying out its children with a GridLayout
+type Grid struct {
+	*gui.Element
+	layout *GridLayout
+}
+
+// NewGrid creates an empty grid with the given columns
+func NewGrid(columns ...Track) *Grid {
+	grid := &Grid{
+		Element: gui.NewElement(0, 0, 0, 0),
+		layout:  NewGridLayout(columns...),
+	}
+	grid.SetLayout(grid.layout)
+	return grid
+}
+
+// SetColumns replaces the column tracks
+func (g *Grid) SetColumns(columns ...Track) *Grid {
+	g.layout.Columns = columns
+	g.InvalidateLayout()
+	return g
+}
+
+// SetRows replaces the row tracks; rows beyond them are auto sized
+func (g *Grid) SetRows(rows ...Track) *Grid {
+	g.layout.Rows = rows
+	g.InvalidateLayout()
+	return g
+}
+
+// SetGap sets the space between rows and between columns
+func (g *Grid) SetGap(rowGap, columnGap int) *Grid {
+	g.layout.RowGap, g.layout.ColumnGap = rowGap, columnGap
+	g.InvalidateLayout()
+	return g
+}
+
+// SetAreas names regions of the grid; see GridLayout.SetAreas
+func (g *Grid) SetAreas(rows ...string) error {
+	if err := g.layout.SetAreas(rows...); err != nil {
+		return err
+	}
+	g.InvalidateLayout()
+	return nil
+}
+
+// Add appends children to the next free cells
+func (g *Grid) Add(children ...gui.GUIElement) *Grid {
+	for _, child := range children {
+		g.AddChild(child)
+	}
+	return g
+}
+
+// Place adds a child in a cell
+func (g *Grid) Place(child gui.GUIElement, row, column int) *Grid {
+	return g.PlaceCell(child, GridCell{Row: row, Column: column})
+}
+
+// PlaceSpan adds a child covering several rows and columns
+func (g *Grid) PlaceSpan(child gui.GUIElement, row, column, rowSpan, columnSpan int) *Grid {
+	return g.PlaceCell(child, GridCell{Row: row, Column: column, RowSpan: rowSpan, ColumnSpan: columnSpan})
+}
+
+// PlaceArea adds a child filling a named area
+func (g *Grid) PlaceArea(child gui.GUIElement, area string) *Grid {
+	return g.PlaceCell(child, GridCell{Row: GridAuto, Column: GridAuto, Area: area})
+}
+
+// PlaceCell adds a child with full control over its placement
+func (g *Grid) PlaceCell(child gui.GUIElement, cell GridCell) *Grid {
+	g.layout.SetCell(child, cell)
+	if g.ChildIndex(child) < 0 {
+		g.AddChild(child)
+	}
+	g.InvalidateLayout()
+	return g
+}
+
+// SetCellAlign sets how a child is placed within its cell
+func (g *Grid) SetCellAlign(child gui.GUIElement, hAlign, vAlign AlignItems) *Grid {
+	cell := g.layout.CellOf(child)
+	cell.HAlign, cell.VAlign = hAlign, vAlign
+	g.layout.SetCell(child, cell)
+	g.InvalidateLayout()
+	return g
+}
+
+// CellOf returns a child's placement
+func (g *Grid) CellOf(child gui.GUIElement) GridCell {
+	return g.layout.CellOf(child)
+}
@@ -1,6 +1,8 @@
 package components
 
 import (
+	"errors"
+	"image"
 	"strings"
 	"unicode"
 	"unicode/utf8"
@@ -25,16 +27,22 @@ type Input struct {
 	bgColor          colorful.Color
 	borderColor      colorful.Color
 	placeholderColor colorful.Color
+	selectionColor   colorful.Color
 	cursorPos        int
 	selectionStart   int
 	selectionEnd     int
 	focused          bool
 	maxLength        int
 	validator        InputValidatorFunc
+	clipboard        gui.Clipboard // nil means the window's clipboard
+	preedit          string        // Text being composed by an input method
+	preeditCursor    int
+	preeditSegments  []gui.CompositionSegment
 	onChange         func(text string)
 	onSubmit         func(text string)
 	onFocus          func()
 	onBlur           func()
+	onClipboardError func(err error)
 }
 
 // NewInput creates a new text input component
@@ -48,17 +56,25 @@ func NewInput() *Input {
 		bgColor:          colorful.Color{R: 1, G: 1, B: 1},       // White
 		borderColor:      colorful.Color{R: 0.7, G: 0.7, B: 0.7}, // Gray
 		placeholderColor: colorful.Color{R: 0.6, G: 0.6, B: 0.6}, // Light gray
+		selectionColor:   colorful.Color{R: 0.7, G: 0.8, B: 1},   // Light blue
 		cursorPos:        0,
 		selectionStart:   -1,
 		selectionEnd:     -1,
 		focused:          false,
 		maxLength:        -1, // No limit
 	}
+	input.SetFocusable(true)
+	input.SetCursor(gui.CursorIBeam)
 
 	// Register event handlers
-	input.AddEventHandler(gui.EventTypeClick, gui.EventHandlerFunc(input.handleClick))
+	input.AddEventHandler(gui.EventTypeMouseDown, gui.EventHandlerFunc(input.handleMouseDown))
+	input.AddEventHandler(gui.EventTypeMouseDrag, gui.EventHandlerFunc(input.handleMouseDrag))
+	input.AddEventHandler(gui.EventTypeDoubleClick, gui.EventHandlerFunc(input.handleDoubleClick))
 	input.AddEventHandler(gui.EventTypeKeyPress, gui.EventHandlerFunc(input.handleKeyPress))
 	input.AddEventHandler(gui.EventTypeTextInput, gui.EventHandlerFunc(input.handleTextInput))
+	input.AddEventHandler(gui.EventTypeCompositionStart, gui.EventHandlerFunc(input.handleCompositionStart))
+	input.AddEventHandler(gui.EventTypeCompositionUpdate, gui.EventHandlerFunc(input.handleCompositionUpdate))
+	input.AddEventHandler(gui.EventTypeCompositionEnd, gui.EventHandlerFunc(input.handleCompositionEnd))
 	input.AddEventHandler(gui.EventTypeFocus, gui.EventHandlerFunc(input.handleFocus))
 	input.AddEventHandler(gui.EventTypeBlur, gui.EventHandlerFunc(input.handleBlur))
 
@@ -84,6 +100,7 @@ func (i *Input) SetText(text string) *Input {
 	i.text = normalizedText
 	i.cursorPos = utf8.RuneCountInString(i.text)
 	i.clearSelection()
+	i.Invalidate()
 
 	if i.onChange != nil {
 		i.onChange(i.text)
@@ -100,30 +117,35 @@ func (i *Input) GetText() string {
 // SetPlaceholder sets placeholder text
 func (i *Input) SetPlaceholder(placeholder string) *Input {
 	i.placeholder = placeholder
+	i.Invalidate()
 	return i
 }
 
 // SetFont updates the input's font
 func (i *Input) SetFont(font font.Face) *Input {
 	i.font = font
+	i.Invalidate()
 	return i
 }
 
 // SetTextColor updates the text color
 func (i *Input) SetTextColor(color colorful.Color) *Input {
 	i.textColor = color
+	i.Invalidate()
 	return i
 }
 
 // SetBackgroundColor updates the background color
 func (i *Input) SetBackgroundColor(color colorful.Color) *Input {
 	i.bgColor = color
+	i.Invalidate()
 	return i
 }
 
 // SetBorderColor updates the border color
 func (i *Input) SetBorderColor(color colorful.Color) *Input {
 	i.borderColor = color
+	i.Invalidate()
 	return i
 }
 
@@ -138,6 +160,7 @@ func (i *Input) SetMaxLength(length int) *Input {
 		if i.cursorPos > length {
 			i.cursorPos = length
 		}
+		i.Invalidate()
 	}
 
 	return i
@@ -155,6 +178,64 @@ func (i *Input) SetOnChange(callback func(text string)) *Input {
 	return i
 }
 
+// SetClipboard sets the clipboard used for cut, copy and paste
+func (i *Input) SetClipboard(clipboard gui.Clipboard) *Input {
+	i.clipboard = clipboard
+	return i
+}
+
+// getClipboard returns the clipboard used for cut, copy and paste: the one
+// set on the input, its window's, or the in-process one while it has no window
+func (i *Input) getClipboard() gui.Clipboard {
+	if i.clipboard != nil {
+		return i.clipboard
+	}
+	if w := i.Window(); w != nil {
+		return w.Clipboard()
+	}
+	return gui.LocalClipboard()
+}
+
+// Copy puts the selected text on the clipboard
+func (i *Input) Copy() error {
+	if !i.hasSelection() {
+		return nil
+	}
+	return gui.WriteClipboardText(i.getClipboard(), i.getSelectedText())
+}
+
+// Cut moves the selected text to the clipboard
+func (i *Input) Cut() error {
+	if !i.hasSelection() {
+		return nil
+	}
+	if err := i.Copy(); err != nil {
+		return err
+	}
+
+	i.deleteSelection()
+	i.Invalidate()
+	if i.onChange != nil {
+		i.onChange(i.text)
+	}
+	return nil
+}
+
+// Paste replaces the selection with the clipboard text, joining lines
+// with spaces since the input holds a single line
+func (i *Input) Paste() error {
+	text, err := gui.ReadClipboardText(i.getClipboard())
+	if err != nil {
+		return err
+	}
+
+	text = strings.NewReplacer("\r\n", " ", "\n", " ", "\r", " ").Replace(text)
+	if text != "" {
+		i.insertText(text)
+	}
+	return nil
+}
+
 // SetOnSubmit sets the onSubmit callback (Enter key)
 func (i *Input) SetOnSubmit(callback func(text string)) *Input {
 	i.onSubmit = callback
@@ -173,19 +254,46 @@ func (i *Input) SetOnBlur(callback func()) *Input {
 	return i
 }
 
-// Focus gives focus to the input
+// SetOnClipboardError sets a callback for clipboard failures during the
+// cut, copy and paste shortcuts; pasting from a clipboard without text is
+// not reported
+func (i *Input) SetOnClipboardError(callback func(err error)) *Input {
+	i.onClipboardError = callback
+	return i
+}
+
+// Focus gives focus to the input, through its window's focus manager once
+// it is part of a window so that keys are routed to it
 func (i *Input) Focus() {
-	i.focused = true
-	if i.onFocus != nil {
-		i.onFocus()
+	if w := i.Window(); w != nil {
+		w.FocusManager().SetFocus(i)
+		return
 	}
+	i.setFocused(true)
 }
 
 // Blur removes focus from the input
 func (i *Input) Blur() {
-	i.focused = false
-	i.clearSelection()
-	if i.onBlur != nil {
+	if w := i.Window(); w != nil && w.FocusManager().Focused() == gui.GUIElement(i) {
+		w.FocusManager().SetFocus(nil)
+		return
+	}
+	if i.focused {
+		i.setFocused(false)
+	}
+}
+
+// setFocused updates the input for gaining or losing focus
+func (i *Input) setFocused(focused bool) {
+	i.focused = focused
+	if !focused {
+		i.clearSelection()
+	}
+	i.Invalidate()
+
+	if focused && i.onFocus != nil {
+		i.onFocus()
+	} else if !focused && i.onBlur != nil {
 		i.onBlur()
 	}
 }
@@ -280,61 +388,121 @@ func (i *Input) insertText(text string) {
 
 	i.text = newText
 	i.cursorPos += len(newRunes)
+	i.Invalidate()
 
 	if i.onChange != nil {
 		i.onChange(i.text)
 	}
 }
 
-// handleClick processes mouse click events
-func (i *Input) handleClick(event gui.Event) bool {
-	clickEvent := event.(*gui.ClickEvent)
+// handleMouseDown places the cursor under the pointer
+func (i *Input) handleMouseDown(event gui.Event) bool {
+	downEvent := event.(*gui.MouseDownEvent)
 
+	// The window's focus manager focuses us before the press arrives
+	if !i.focused || !i.ContainsPoint(downEvent.X, downEvent.Y) {
+		return false
+	}
+
+	// Anchor a selection that dragging extends
+	i.cursorPos = i.cursorPosAt(downEvent.LocalX)
+	i.selectionStart = i.cursorPos
+	i.selectionEnd = i.cursorPos
+	i.Invalidate()
+	return true
+}
+
+// handleMouseDrag extends the selection from the press position to the pointer
+func (i *Input) handleMouseDrag(event gui.Event) bool {
+	if !i.focused || i.selectionStart < 0 {
+		return false
+	}
+
+	dragEvent := event.(*gui.MouseDragEvent)
+	i.cursorPos = i.cursorPosAt(dragEvent.LocalX)
+	i.selectionEnd = i.cursorPos
+	i.Invalidate()
+	return true
+}
+
+// handleDoubleClick selects the word under the pointer
+func (i *Input) handleDoubleClick(event gui.Event) bool {
+	clickEvent := event.(*gui.DoubleClickEvent)
 	if !i.ContainsPoint(clickEvent.X, clickEvent.Y) {
-		if i.focused {
-			i.Blur()
-		}
 		return false
 	}
 
-	if !i.focused {
-		i.Focus()
+	runes := []rune(i.text)
+	pos := i.cursorPosAt(clickEvent.LocalX)
+	isWordRune := func(r rune) bool {
+		return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_'
 	}
 
-	// Calculate cursor position from click location
-	x, _, _, _ := i.GetBounds()
-	relativeX := clickEvent.X - x - 5 // Account for padding
+	start, end := pos, pos
+	for start > 0 && isWordRune(runes[start-1]) {
+		start--
+	}
+	for end < len(runes) && isWordRune(runes[end]) {
+		end++
+	}
+
+	i.selectionStart = start
+	i.selectionEnd = end
+	i.cursorPos = end
+	i.Invalidate()
+	return true
+}
+
+// cursorPosAt returns the character position closest to an x coordinate
+// local to the input
+func (i *Input) cursorPosAt(localX int) int {
+	relativeX := localX - 5 // Account for padding
 
 	if relativeX <= 0 {
-		i.cursorPos = 0
-	} else {
-		// Find closest character position
-		runes := []rune(i.text)
-		width := 0
+		return 0
+	}
 
-		for pos, r := range runes {
-			charWidth := getCharWidth(r, i.font)
-			if width+charWidth/2 > relativeX {
-				i.cursorPos = pos
-				break
-			}
-			width += charWidth
-			i.cursorPos = pos + 1
+	// Find closest character position
+	runes := []rune(i.text)
+	width := 0
+	for pos, r := range runes {
+		charWidth := getCharWidth(r, i.font)
+		if width+charWidth/2 > relativeX {
+			return pos
 		}
+		width += charWidth
 	}
 
-	i.clearSelection()
-	return true
+	return len(runes)
 }
 
 // handleKeyPress processes keyboard input
 func (i *Input) handleKeyPress(event gui.Event) bool {
-	if !i.focused {
+	if !i.focused || event.IsDefaultPrevented() {
 		return false
 	}
 
+	// Keys belong to the input method while it is composing
+	if i.preedit != "" {
+		return true
+	}
+
 	keyEvent := event.(*gui.KeyPressEvent)
 
+	// Every handled key edits the text or moves the cursor
+	handled := i.applyKey(keyEvent)
+	if handled {
+		i.Invalidate()
+	}
+	return handled
+}
+
+// applyKey performs the editing action bound to a key press
+func (i *Input) applyKey(keyEvent *gui.KeyPressEvent) bool {
+	if i.applyClipboardKey(keyEvent) {
+		return true
+	}
+
 	switch keyEvent.Key {
 	case gui.KeyBackspace:
 		if i.hasSelection() {
@@ -366,20 +534,22 @@ func (i *Input) handleKeyPress(event gui.Event) bool {
 		return true
 
 	case gui.KeyArrowLeft:
-		if i.cursorPos > 0 {
-			i.cursorPos--
-		}
-		i.clearSelection()
+		i.moveCursor(i.cursorPos-1, keyEvent.Modifiers.Has(gui.ModifierShift))
 		return true
 
 	case gui.KeyArrowRight:
-		if i.cursorPos < utf8.RuneCountInString(i.text) {
-			i.cursorPos++
-		}
-		i.clearSelection()
+		i.moveCursor(i.cursorPos+1, keyEvent.Modifiers.Has(gui.ModifierShift))
 		return true
 
-	case gui.KeyEnter:
+	case gui.KeyHome:
+		i.moveCursor(0, keyEvent.Modifiers.Has(gui.ModifierShift))
+		return true
+
+	case gui.KeyEnd:
+		i.moveCursor(utf8.RuneCountInString(i.text), keyEvent.Modifiers.Has(gui.ModifierShift))
+		return true
+
+	case gui.KeyEnter, gui.KeyNumpadEnter:
 		if i.onSubmit != nil {
 			i.onSubmit(i.text)
 		}
@@ -389,9 +559,56 @@ func (i *Input) handleKeyPress(event gui.Event) bool {
 	return false
 }
 
+// applyClipboardKey handles Ctrl+C, Ctrl+X and Ctrl+V along with their
+// Ctrl+Insert, Shift+Delete and Shift+Insert equivalents
+func (i *Input) applyClipboardKey(keyEvent *gui.KeyPressEvent) bool {
+	mods := keyEvent.Modifiers & (gui.ModifierCtrl | gui.ModifierShift | gui.ModifierAlt | gui.ModifierSuper)
+
+	var err error
+	switch {
+	case mods == gui.ModifierCtrl && keyEvent.Key == gui.KeyC,
+		mods == gui.ModifierCtrl && keyEvent.Key == gui.KeyInsert:
+		err = i.Copy()
+	case mods == gui.ModifierCtrl && keyEvent.Key == gui.KeyX,
+		mods == gui.ModifierShift && keyEvent.Key == gui.KeyDelete:
+		err = i.Cut()
+	case mods == gui.ModifierCtrl && keyEvent.Key == gui.KeyV,
+		mods == gui.ModifierShift && keyEvent.Key == gui.KeyInsert:
+		err = i.Paste()
+	default:
+		return false
+	}
+
+	if err != nil && !errors.Is(err, gui.ErrClipboardFormat) && i.onClipboardError != nil {
+		i.onClipboardError(err)
+	}
+	return true
+}
+
+// moveCursor places the cursor, extending the selection when selecting is set
+func (i *Input) moveCursor(pos int, selecting bool) {
+	if pos < 0 {
+		pos = 0
+	}
+	if length := utf8.RuneCountInString(i.text); pos > length {
+		pos = length
+	}
+
+	if !selecting {
+		i.clearSelection()
+	} else if i.selectionStart < 0 {
+		i.selectionStart = i.cursorPos
+	}
+
+	i.cursorPos = pos
+	if selecting {
+		i.selectionEnd = pos
+	}
+}
+
 // handleTextInput processes text input events
 func (i *Input) handleTextInput(event gui.Event) bool {
-	if !i.focused {
+	if !i.focused || event.IsDefaultPrevented() {
 		return false
 	}
 
@@ -412,18 +629,102 @@ func (i *Input) handleTextInput(event gui.Event) bool {
 	return true
 }
 
+// handleCompositionStart replaces the selection with the coming composition
+func (i *Input) handleCompositionStart(event gui.Event) bool {
+	if !i.focused || event.IsDefaultPrevented() {
+		return false
+	}
+
+	if i.hasSelection() {
+		i.deleteSelection()
+		if i.onChange != nil {
+			i.onChange(i.text)
+		}
+	}
+	i.Invalidate()
+	return true
+}
+
+// handleCompositionUpdate shows the input method's pre-edit text inline
+func (i *Input) handleCompositionUpdate(event gui.Event) bool {
+	if !i.focused || event.IsDefaultPrevented() {
+		return false
+	}
+
+	update := event.(*gui.CompositionUpdateEvent)
+	i.preedit = update.Text
+	i.preeditCursor = update.Cursor
+	i.preeditSegments = update.Segments
+	i.Invalidate()
+	return true
+}
+
+// handleCompositionEnd drops the pre-edit text; committed text arrives as TextInputEvent
+func (i *Input) handleCompositionEnd(event gui.Event) bool {
+	if i.preedit == "" && i.preeditSegments == nil {
+		return false
+	}
+
+	i.clearComposition()
+	i.Invalidate()
+	return true
+}
+
+// clearComposition forgets the pre-edit state
+func (i *Input) clearComposition() {
+	i.preedit = ""
+	i.preeditCursor = 0
+	i.preeditSegments = nil
+}
+
+// CaretRect returns the caret in window coordinates while the input is
+// focused, so the input method can place its candidate window next to it
+func (i *Input) CaretRect() image.Rectangle {
+	if !i.focused {
+		return image.Rectangle{}
+	}
+
+	_, _, _, height := i.GetBounds()
+	caretX := 5 + i.getCursorPixelPosition() + i.preeditOffset(i.preeditCursor)
+	x0, y0 := i.LocalToWindow(caretX, 2)
+	x1, y1 := i.LocalToWindow(caretX+1, height-2)
+	return image.Rect(x0, y0, x1, y1).Canon()
+}
+
+// preeditOffset returns the pixel width of the pre-edit text before a rune position
+func (i *Input) preeditOffset(pos int) int {
+	runes := []rune(i.preedit)
+	if pos > len(runes) {
+		pos = len(runes)
+	}
+	if pos < 0 {
+		pos = 0
+	}
+	return textWidth(string(runes[:pos]), i.font)
+}
+
 // handleFocus processes focus events
 func (i *Input) handleFocus(event gui.Event) bool {
-	i.Focus()
+	i.setFocused(true)
 	return true
 }
 
 // handleBlur processes blur events
 func (i *Input) handleBlur(event gui.Event) bool {
-	i.Blur()
+	i.clearComposition()
+	i.setFocused(false)
 	return true
 }
 
+// textWidth calculates the width of a string
+func textWidth(text string, font font.Face) int {
+	width := 0
+	for _, r := range text {
+		width += getCharWidth(r, font)
+	}
+	return width
+}
+
 // getCharWidth calculates the width of a character
 func getCharWidth(r rune, font font.Face) int {
 	if font == nil {
@@ -460,7 +761,24 @@ func (i *Input) Render(canvas gui.Canvas) error {
 	textX := x + 5            // Padding
 	textY := y + height/2 + 4 // Center vertically
 
+	if i.focused && i.preedit != "" {
+		return i.renderComposition(canvas, textX, textY, y, height)
+	}
+
 	if i.text != "" {
+		// Highlight the selection behind the text
+		if i.focused && i.hasSelection() {
+			start, end := i.selectionStart, i.selectionEnd
+			if start > end {
+				start, end = end, start
+			}
+			startX := textX + i.pixelOffset(start)
+			endX := textX + i.pixelOffset(end)
+			if err := canvas.DrawRectangle(startX, y+2, endX-startX, height-4, i.selectionColor, true); err != nil {
+				return err
+			}
+		}
+
 		// Render actual text
 		if err := canvas.DrawText(i.text, textX, textY, i.font, i.textColor); err != nil {
 			return err
@@ -484,6 +802,51 @@ func (i *Input) Render(canvas gui.Canvas) error {
 	return nil
 }
 
+// renderComposition draws the text with the input method's pre-edit text
+// inserted at the cursor, underlined per segment, and the caret within it
+func (i *Input) renderComposition(canvas gui.Canvas, textX, textY, y, height int) error {
+	runes := []rune(i.text)
+	pos := i.cursorPos
+	if pos > len(runes) {
+		pos = len(runes)
+	}
+
+	preeditX := textX + i.pixelOffset(pos)
+	preeditWidth := textWidth(i.preedit, i.font)
+
+	if err := canvas.DrawText(string(runes[:pos]), textX, textY, i.font, i.textColor); err != nil {
+		return err
+	}
+	if err := canvas.DrawText(i.preedit, preeditX, textY, i.font, i.textColor); err != nil {
+		return err
+	}
+	if err := canvas.DrawText(string(runes[pos:]), preeditX+preeditWidth, textY, i.font, i.textColor); err != nil {
+		return err
+	}
+
+	// Underline each segment, thicker for the one being converted
+	segments := i.preeditSegments
+	if len(segments) == 0 {
+		segments = []gui.CompositionSegment{{Start: 0, End: utf8.RuneCountInString(i.preedit)}}
+	}
+	underlineY := y + height - 5
+	for _, segment := range segments {
+		startX := preeditX + i.preeditOffset(segment.Start)
+		endX := preeditX + i.preeditOffset(segment.End)
+		thickness := 1
+		if segment.Attr == gui.CompositionTarget {
+			thickness = 2
+		}
+		// Leave a pixel between segments so their boundaries show
+		if err := canvas.DrawRectangle(startX, underlineY, endX-startX-1, thickness, i.textColor, true); err != nil {
+			return err
+		}
+	}
+
+	cursorX := preeditX + i.preeditOffset(i.preeditCursor)
+	return canvas.DrawRectangle(cursorX, y+2, 1, height-4, i.textColor, true)
+}
+
 // getCursorPixelPosition calculates the pixel position of the cursor
 func (i *Input) getCursorPixelPosition() int {
 	if i.cursorPos <= 0 {
@@ -495,8 +858,14 @@ func (i *Input) getCursorPixelPosition() int {
 		i.cursorPos = len(runes)
 	}
 
+	return i.pixelOffset(i.cursorPos)
+}
+
+// pixelOffset calculates the pixel width of the text before a character position
+func (i *Input) pixelOffset(pos int) int {
+	runes := []rune(i.text)
 	width := 0
-	for j := 0; j < i.cursorPos && j < len(runes); j++ {
+	for j := 0; j < pos && j < len(runes); j++ {
 		width += getCharWidth(runes[j], i.font)
 	}
 
@@ -28,6 +28,7 @@ type Label struct {
 	alignment TextAlignment
 	wordWrap  bool
 	autoSize  bool
+	ellipsis  bool
 }
 
 // NewLabel creates a new label with specified text
@@ -61,6 +62,8 @@ func (l *Label) SetText(text string) *Label {
 		l.updateSize()
 	}
 
+	l.Invalidate()
+	l.InvalidateLayout()
 	return l
 }
 
@@ -77,24 +80,38 @@ func (l *Label) SetFont(font font.Face) *Label {
 		l.updateSize()
 	}
 
+	l.Invalidate()
+	l.InvalidateLayout()
 	return l
 }
 
 // SetColor updates the text color
 func (l *Label) SetColor(color colorful.Color) *Label {
 	l.color = color
+	l.Invalidate()
 	return l
 }
 
 // SetAlignment updates text alignment
 func (l *Label) SetAlignment(alignment TextAlignment) *Label {
 	l.alignment = alignment
+	l.Invalidate()
 	return l
 }
 
 // SetWordWrap enables or disables word wrapping
 func (l *Label) SetWordWrap(enable bool) *Label {
 	l.wordWrap = enable
+	l.Invalidate()
+	l.InvalidateLayout()
+	return l
+}
+
+// SetEllipsis enables or disables shortening single-line text that is wider
+// than the label, ending it with an ellipsis
+func (l *Label) SetEllipsis(enable bool) *Label {
+	l.ellipsis = enable
+	l.Invalidate()
 	return l
 }
 
@@ -106,6 +123,8 @@ func (l *Label) SetAutoSize(enable bool) *Label {
 		l.updateSize()
 	}
 
+	l.Invalidate()
+	l.InvalidateLayout()
 	return l
 }
 
@@ -146,6 +165,30 @@ func (l *Label) updateSize() {
 	l.SetSize(maxWidth, totalHeight)
 }
 
+// MeasureContent returns the size of the text, wrapped to the available
+// width when word wrapping is on; without a width limit it is one line
+func (l *Label) MeasureContent(constraints gui.Constraints) gui.Size {
+	if l.font == nil || l.text == "" {
+		return gui.Size{}
+	}
+
+	metrics := l.font.Metrics()
+	lineHeight := (metrics.Ascent + metrics.Descent).Ceil()
+
+	if !l.wordWrap || constraints.MaxWidth >= gui.Unbounded {
+		return gui.Size{Width: measureTextWidth(l.text, l.font), Height: lineHeight}
+	}
+
+	lines := l.wrapText(l.text, constraints.MaxWidth)
+	maxWidth := 0
+	for _, line := range lines {
+		if lineWidth := measureTextWidth(line, l.font); lineWidth > maxWidth {
+			maxWidth = lineWidth
+		}
+	}
+	return gui.Size{Width: maxWidth, Height: len(lines) * lineHeight}
+}
+
 // wrapText breaks text into lines that fit within the specified width
 func (l *Label) wrapText(text string, maxWidth int) []string {
 	if maxWidth <= 0 {
@@ -205,13 +248,17 @@ func measureTextWidth(text string, font font.Face) int {
 	return width
 }
 
-// Render draws the label to the canvas
+// Render draws the label to the canvas, inside its padding
 func (l *Label) Render(canvas gui.Canvas) error {
 	if !l.IsVisible() || l.text == "" {
 		return nil
 	}
 
 	x, y, width, _ := l.GetBounds()
+	padding := l.Padding()
+	x += padding.Left
+	y += padding.Top
+	width -= padding.Horizontal()
 
 	if l.wordWrap && width > 0 {
 		return l.renderMultiLine(canvas, x, y, width)
@@ -222,24 +269,54 @@ func (l *Label) Render(canvas gui.Canvas) error {
 
 // renderSingleLine renders text as a single line
 func (l *Label) renderSingleLine(canvas gui.Canvas, x, y, width int) error {
-	textX := x
-
-	// Apply horizontal alignment
-	if l.alignment != AlignLeft && width > 0 {
-		textWidth := measureTextWidth(l.text, l.font)
-		switch l.alignment {
-		case AlignCenter:
-			textX = x + (width-textWidth)/2
-		case AlignRight:
-			textX = x + width - textWidth
-		}
+	text := l.text
+	if l.ellipsis && width > 0 {
+		text = ellipsize(text, l.font, width)
 	}
+	textX := alignTextX(l.alignment, x, width, measureTextWidth(text, l.font))
 
 	// Position text at baseline
 	metrics := l.font.Metrics()
 	textY := y + metrics.Ascent.Ceil()
 
-	return canvas.DrawText(l.text, textX, textY, l.font, l.color)
+	return canvas.DrawText(text, textX, textY, l.font, l.color)
+}
+
+// alignTextX returns where text of a given width starts when aligned
+// within a span; without a span it starts at x
+func alignTextX(alignment TextAlignment, x, width, textWidth int) int {
+	if width <= 0 {
+		return x
+	}
+	switch alignment {
+	case AlignCenter:
+		return x + (width-textWidth)/2
+	case AlignRight:
+		return x + width - textWidth
+	}
+	return x
+}
+
+// ellipsize shortens text to fit a width, replacing the end with an
+// ellipsis; text that fits is returned unchanged
+func ellipsize(text string, face font.Face, width int) string {
+	if measureTextWidth(text, face) <= width {
+		return text
+	}
+	const ellipsis = "..."
+	room := width - measureTextWidth(ellipsis, face)
+	runes := []rune(text)
+	used := 0
+	for i, r := range runes {
+		advance, ok := face.GlyphAdvance(r)
+		if ok {
+			used += advance.Ceil()
+		}
+		if used > room {
+			return strings.TrimRight(string(runes[:i]), " ") + ellipsis
+		}
+	}
+	return text
 }
 
 // renderMultiLine renders wrapped text across multiple lines
@@ -250,18 +327,7 @@ func (l *Label) renderMultiLine(canvas gui.Canvas, x, y, width int) error {
 
 	for i, line := range lines {
 		lineY := y + i*lineHeight + metrics.Ascent.Ceil()
-		lineX := x
-
-		// Apply horizontal alignment for each line
-		if l.alignment != AlignLeft {
-			lineWidth := measureTextWidth(line, l.font)
-			switch l.alignment {
-			case AlignCenter:
-				lineX = x + (width-lineWidth)/2
-			case AlignRight:
-				lineX = x + width - lineWidth
-			}
-		}
+		lineX := alignTextX(l.alignment, x, width, measureTextWidth(line, l.font))
 
 		if err := canvas.DrawText(line, lineX, lineY, l.font, l.color); err != nil {
 			return err
@@ -0,0 +1,777 @@
+package components
+
+import (
+	"fmt"
+	"image"
+	"sort"
+
+	"github.com/lucasb-eyer/go-colorful"
+	"github.com/opd-ai/gui"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+)
+
+// DefaultRowHeight is the height of list rows unless set otherwise
+const DefaultRowHeight = 22
+
+// ListDataSource supplies the items of a ListView by index, so the items
+// need not exist as elements, or at all, until they are shown
+type ListDataSource interface {
+	// Count returns the number of items
+	Count() int
+
+	// Item returns the item at index, which is within [0, Count())
+	Item(index int) interface{}
+}
+
+// StringList is a ListDataSource over a slice of strings
+type StringList []string
+
+// Count returns the number of strings
+func (l StringList) Count() int {
+	return len(l)
+}
+
+// Item returns the string at index
+func (l StringList) Item(index int) interface{} {
+	return l[index]
+}
+
+// ListRowDelegate creates the elements that show list items. A ListView
+// only keeps rows for the visible items and binds rows scrolled out of view
+// to other items, so a row must show whatever it was bound to last.
+type ListRowDelegate interface {
+	// CreateRow returns a new, unbound row element
+	CreateRow() gui.GUIElement
+
+	// BindRow makes row show an item
+	BindRow(row gui.GUIElement, index int, item interface{}, selected bool)
+}
+
+// SelectionMode controls how many items of a list can be selected
+type SelectionMode int
+
+const (
+	SelectionNone     SelectionMode = iota // Items cannot be selected
+	SelectionSingle                        // One item at a time
+	SelectionMultiple                      // Any number, with Ctrl and Shift
+)
+
+// ListView shows the items of a data source in a scrolling column of rows,
+// creating rows only for the visible items and reusing them as it scrolls.
+// Rows are DefaultRowHeight high unless given a fixed height or a height
+// per item.
+type ListView struct {
+	*ScrollView
+	body *listBody
+
+	source     ListDataSource
+	delegate   ListRowDelegate
+	count      int // Items in the source at the last Reload
+	rowHeight  int // Fixed height used without a height function
+	heightFunc func(index int) int
+	offsets    []int // Top of each row and the total height, built on demand for variable heights
+	width      int   // Width of the rows when wider than the viewport, set by tables
+
+	rows map[int]gui.GUIElement // Bound rows by item index
+	pool []gui.GUIElement       // Unbound rows ready for reuse
+
+	mode      SelectionMode
+	selection indexSet
+	current   int // Item the keyboard is on
+	anchor    int // Item Shift extends the selection from
+	focused   bool
+
+	selectionColor colorful.Color
+	currentColor   colorful.Color
+
+	onSelectionChanged func()
+	onActivate         func(index int)
+}
+
+// NewListView creates a list of the items in source, shown as text rows
+func NewListView(source ListDataSource) *ListView {
+	l := &ListView{
+		ScrollView:     NewScrollView(nil),
+		delegate:       TextRowDelegate{},
+		rowHeight:      DefaultRowHeight,
+		rows:           make(map[int]gui.GUIElement),
+		mode:           SelectionSingle,
+		selectionColor: colorful.Color{R: 0.8, G: 0.87, B: 1},  // Light blue
+		currentColor:   colorful.Color{R: 0.2, G: 0.4, B: 0.9}, // Blue
+	}
+	l.body = &listBody{Element: gui.NewElement(0, 0, 0, 0), list: l}
+	l.body.SetLayout(listLayout{l})
+	l.SetScrollbars(ScrollbarNever, ScrollbarAuto)
+	l.SetContent(l.body)
+	l.scrolled = l.body.InvalidateLayout // Rows for the new range are bound before the next paint
+	l.SetDataSource(source)
+
+	// Capture handlers run before the scroll view's own, which would
+	// otherwise scroll on the arrow keys
+	l.AddCaptureHandler(gui.EventTypeKeyPress, gui.EventHandlerFunc(l.handleKeyPress))
+	l.AddEventHandler(gui.EventTypeClick, gui.EventHandlerFunc(l.handleClick))
+	l.AddEventHandler(gui.EventTypeDoubleClick, gui.EventHandlerFunc(l.handleDoubleClick))
+	l.AddEventHandler(gui.EventTypeFocus, gui.EventHandlerFunc(l.handleFocus))
+	l.AddEventHandler(gui.EventTypeBlur, gui.EventHandlerFunc(l.handleBlur))
+
+	return l
+}
+
+// SetDataSource replaces the items, clearing the selection and scrolling
+// back to the top
+func (l *ListView) SetDataSource(source ListDataSource) *ListView {
+	l.source = source
+	l.selection = nil
+	l.current, l.anchor = 0, 0
+	l.ScrollTo(0, 0)
+	l.Reload()
+	return l
+}
+
+// DataSource returns the list's data source
+func (l *ListView) DataSource() ListDataSource {
+	return l.source
+}
+
+// SetRowDelegate sets what creates and binds rows, discarding existing rows
+func (l *ListView) SetRowDelegate(delegate ListRowDelegate) *ListView {
+	l.delegate = delegate
+	for index, row := range l.rows {
+		l.body.RemoveChild(row)
+		delete(l.rows, index)
+	}
+	l.pool = nil
+	l.body.InvalidateLayout()
+	return l
+}
+
+// SetRowHeight gives every row the same height
+func (l *ListView) SetRowHeight(height int) *ListView {
+	l.rowHeight = maxInt(height, 1)
+	l.heightFunc = nil
+	l.offsets = nil
+	l.body.InvalidateLayout()
+	return l
+}
+
+// SetRowHeightFunc gives each row its own height; positions are summed over
+// all items once per Reload, so fixed heights suit very long lists better
+func (l *ListView) SetRowHeightFunc(height func(index int) int) *ListView {
+	l.heightFunc = height
+	l.offsets = nil
+	l.body.InvalidateLayout()
+	return l
+}
+
+// SetSelectionMode sets how many items can be selected, trimming the
+// selection to fit
+func (l *ListView) SetSelectionMode(mode SelectionMode) *ListView {
+	l.mode = mode
+	switch {
+	case mode == SelectionNone:
+		l.setSelection(nil)
+	case mode == SelectionSingle && len(l.selection) > 0:
+		l.setSelection(indexSet{}.add(l.selection.first(), l.selection.first()+1))
+	}
+	return l
+}
+
+// SetSelectionColors sets the background of selected rows and the outline
+// of the keyboard's row while the list has focus
+func (l *ListView) SetSelectionColors(selection, current colorful.Color) *ListView {
+	l.selectionColor, l.currentColor = selection, current
+	l.repaint()
+	return l
+}
+
+// SetOnSelectionChanged sets a callback run whenever the selection changes
+func (l *ListView) SetOnSelectionChanged(callback func()) *ListView {
+	l.onSelectionChanged = callback
+	return l
+}
+
+// SetOnActivate sets a callback run when an item is double-clicked or
+// Enter is pressed on it
+func (l *ListView) SetOnActivate(callback func(index int)) *ListView {
+	l.onActivate = callback
+	return l
+}
+
+// Reload picks up changes to the data source, rebinding the visible rows
+// and dropping selected items past the new end
+func (l *ListView) Reload() {
+	l.count = 0
+	if l.source != nil {
+		l.count = l.source.Count()
+	}
+	l.offsets = nil
+	l.selection = l.selection.truncate(l.count)
+	l.current = clampConstraint(l.current, 0, maxInt(l.count-1, 0))
+	l.anchor = clampConstraint(l.anchor, 0, maxInt(l.count-1, 0))
+
+	for index, row := range l.rows {
+		if index < l.count {
+			l.bind(row, index)
+			continue
+		}
+		// Rows past the end must not be bound again before the next layout
+		delete(l.rows, index)
+		l.body.RemoveChild(row)
+		l.pool = append(l.pool, row)
+	}
+	l.body.InvalidateLayout()
+	l.repaint()
+}
+
+// Count returns the number of items as of the last Reload
+func (l *ListView) Count() int {
+	return l.count
+}
+
+// IsSelected reports whether an item is selected
+func (l *ListView) IsSelected(index int) bool {
+	return l.selection.contains(index)
+}
+
+// SelectedIndex returns the first selected item, or -1
+func (l *ListView) SelectedIndex() int {
+	return l.selection.first()
+}
+
+// SelectedIndices returns the selected items in order
+func (l *ListView) SelectedIndices() []int {
+	return l.selection.indices()
+}
+
+// SelectionCount returns the number of selected items
+func (l *ListView) SelectionCount() int {
+	return l.selection.count()
+}
+
+// Select selects only the item at index and moves the keyboard to it
+func (l *ListView) Select(index int) {
+	if l.mode == SelectionNone || index < 0 || index >= l.count {
+		return
+	}
+	l.current, l.anchor = index, index
+	l.setSelection(indexSet{}.add(index, index+1))
+}
+
+// SelectRange selects the items from first to last inclusive, in addition
+// to those already selected; single selection keeps only last
+func (l *ListView) SelectRange(first, last int) {
+	if l.mode == SelectionNone || l.count == 0 {
+		return
+	}
+	if l.mode == SelectionSingle {
+		l.Select(last)
+		return
+	}
+	first, last = clampConstraint(first, 0, l.count-1), clampConstraint(last, 0, l.count-1)
+	if first > last {
+		first, last = last, first
+	}
+	l.setSelection(l.selection.add(first, last+1))
+}
+
+// SelectAll selects every item when multiple selection is on
+func (l *ListView) SelectAll() {
+	if l.mode == SelectionMultiple && l.count > 0 {
+		l.setSelection(indexSet{}.add(0, l.count))
+	}
+}
+
+// ClearSelection deselects every item
+func (l *ListView) ClearSelection() {
+	l.setSelection(nil)
+}
+
+// CurrentIndex returns the item the keyboard is on
+func (l *ListView) CurrentIndex() int {
+	return l.current
+}
+
+// RowAt returns the item under a point in the list's coordinates, or -1
+func (l *ListView) RowAt(x, y int) int {
+	viewport := l.viewport()
+	if !image.Pt(x, y).In(viewport) {
+		return -1
+	}
+	_, scrollY := l.ScrollPosition()
+	index := l.indexAt(y - viewport.Min.Y + scrollY)
+	if index >= l.count {
+		return -1
+	}
+	return index
+}
+
+// RowElement returns the row showing an item, or nil when it is not visible
+func (l *ListView) RowElement(index int) gui.GUIElement {
+	return l.rows[index]
+}
+
+// ScrollToIndex scrolls as little as possible to bring an item into view
+func (l *ListView) ScrollToIndex(index int) {
+	if index < 0 || index >= l.count {
+		return
+	}
+	// The body may not have been sized for a count that just grew
+	l.UpdateLayout()
+
+	top, bottom := l.rowTop(index), l.rowTop(index+1)
+	viewport := l.viewport()
+	x, y := l.ScrollPosition()
+	switch {
+	case top < y || bottom-top > viewport.Dy():
+		y = top
+	case bottom > y+viewport.Dy():
+		y = bottom - viewport.Dy()
+	}
+	l.ScrollTo(x, y)
+}
+
+// Activate runs the activation callback for an item
+func (l *ListView) Activate(index int) {
+	if l.onActivate != nil && index >= 0 && index < l.count {
+		l.onActivate(index)
+	}
+}
+
+// setSelection replaces the selection, rebinding rows and running the
+// callback when it changed
+func (l *ListView) setSelection(selection indexSet) {
+	if selection.equal(l.selection) {
+		return
+	}
+	l.selection = selection
+	for index, row := range l.rows {
+		l.bind(row, index)
+	}
+	l.repaint()
+	if l.onSelectionChanged != nil {
+		l.onSelectionChanged()
+	}
+}
+
+// moveTo moves the keyboard to an item: alone it selects the item, Shift
+// extends the selection from the anchor and Ctrl leaves it as it is
+func (l *ListView) moveTo(index int, modifiers gui.KeyModifiers) {
+	if l.count == 0 {
+		return
+	}
+	index = clampConstraint(index, 0, l.count-1)
+	switch {
+	case l.mode == SelectionMultiple && modifiers.Has(gui.ModifierShift):
+		l.current = index
+		l.setSelection(indexSet{}.add(minInt(l.anchor, index), maxInt(l.anchor, index)+1))
+	case l.mode == SelectionMultiple && modifiers.Has(gui.ModifierCtrl):
+		l.current, l.anchor = index, index
+	case l.mode == SelectionNone:
+		l.current, l.anchor = index, index
+	default:
+		l.Select(index)
+	}
+	l.ScrollToIndex(index)
+	l.repaint()
+}
+
+// toggle flips an item's selection, the Ctrl-click of multiple selection
+func (l *ListView) toggle(index int) {
+	l.current, l.anchor = index, index
+	if l.selection.contains(index) {
+		l.setSelection(l.selection.remove(index))
+	} else {
+		l.setSelection(l.selection.add(index, index+1))
+	}
+}
+
+// handleKeyPress moves through the items with the arrow keys, PageUp,
+// PageDown, Home and End, selects with Space and Ctrl+A, and activates
+// with Enter
+func (l *ListView) handleKeyPress(event gui.Event) bool {
+	key := event.(*gui.KeyPressEvent)
+	if key.Target() != l || event.IsDefaultPrevented() || l.count == 0 {
+		return false
+	}
+
+	page := maxInt(l.indexAt(l.rowTop(l.current)+l.viewport().Dy())-l.current, 1)
+	switch key.Key {
+	case gui.KeyArrowUp:
+		l.moveTo(l.current-1, key.Modifiers)
+	case gui.KeyArrowDown:
+		l.moveTo(l.current+1, key.Modifiers)
+	case gui.KeyPageUp:
+		l.moveTo(l.current-page, key.Modifiers)
+	case gui.KeyPageDown:
+		l.moveTo(l.current+page, key.Modifiers)
+	case gui.KeyHome:
+		l.moveTo(0, key.Modifiers)
+	case gui.KeyEnd:
+		l.moveTo(l.count-1, key.Modifiers)
+	case gui.KeySpace:
+		if l.mode == SelectionMultiple && key.Modifiers.Has(gui.ModifierCtrl) {
+			l.toggle(l.current)
+		} else {
+			l.Select(l.current)
+		}
+	case gui.KeyA:
+		if l.mode != SelectionMultiple || !key.Modifiers.Has(gui.ModifierCtrl) {
+			return false
+		}
+		l.SelectAll()
+	case gui.KeyEnter, gui.KeyNumpadEnter:
+		l.Activate(l.current)
+	default:
+		return false
+	}
+	return true
+}
+
+// handleClick selects the clicked item, toggling it with Ctrl and
+// selecting up to it with Shift
+func (l *ListView) handleClick(event gui.Event) bool {
+	click := event.(*gui.ClickEvent)
+	index := l.RowAt(click.LocalX, click.LocalY)
+	if click.Button != gui.MouseButtonLeft || index < 0 {
+		return false
+	}
+
+	switch {
+	case l.mode == SelectionMultiple && click.Modifiers.Has(gui.ModifierCtrl):
+		l.toggle(index)
+	case l.mode == SelectionMultiple && click.Modifiers.Has(gui.ModifierShift):
+		l.current = index
+		l.setSelection(indexSet{}.add(minInt(l.anchor, index), maxInt(l.anchor, index)+1))
+	case l.mode == SelectionNone:
+		l.current, l.anchor = index, index
+	default:
+		l.Select(index)
+	}
+	l.repaint()
+	return true
+}
+
+// handleDoubleClick activates the item under the pointer
+func (l *ListView) handleDoubleClick(event gui.Event) bool {
+	click := event.(*gui.DoubleClickEvent)
+	index := l.RowAt(click.LocalX, click.LocalY)
+	if click.Button != gui.MouseButtonLeft || index < 0 {
+		return false
+	}
+	l.Activate(index)
+	return true
+}
+
+// handleFocus shows the keyboard's row while the list has focus
+func (l *ListView) handleFocus(event gui.Event) bool {
+	if event.(*gui.FocusEvent).Target() == l {
+		l.focused = true
+		l.repaint()
+	}
+	return false
+}
+
+// handleBlur hides the keyboard's row
+func (l *ListView) handleBlur(event gui.Event) bool {
+	if event.(*gui.BlurEvent).Target() == l {
+		l.focused = false
+		l.repaint()
+	}
+	return false
+}
+
+// bind shows an item in a row
+func (l *ListView) bind(row gui.GUIElement, index int) {
+	l.delegate.BindRow(row, index, l.source.Item(index), l.selection.contains(index))
+}
+
+// repaint redraws the visible rows
+func (l *ListView) repaint() {
+	_, _, width, height := l.GetBounds()
+	l.InvalidateRect(0, 0, width, height)
+}
+
+// rowTop returns where an item's row starts within the body; index may be
+// Count, giving the total height
+func (l *ListView) rowTop(index int) int {
+	if l.heightFunc == nil {
+		return index * l.rowHeight
+	}
+	if l.offsets == nil {
+		l.offsets = make([]int, l.count+1)
+		for i := 0; i < l.count; i++ {
+			l.offsets[i+1] = l.offsets[i] + maxInt(l.heightFunc(i), 1)
+		}
+	}
+	return l.offsets[clampConstraint(index, 0, l.count)]
+}
+
+// indexAt returns the item whose row spans a height within the body,
+// which is Count past the last row
+func (l *ListView) indexAt(y int) int {
+	if y < 0 {
+		return 0
+	}
+	if l.heightFunc == nil {
+		return minInt(y/l.rowHeight, l.count)
+	}
+	l.rowTop(0) // Builds the offsets
+	return sort.Search(l.count, func(i int) bool { return l.offsets[i+1] > y })
+}
+
+// realize binds rows to the visible items, returning rows scrolled out of
+// view to the pool, and places them down the body
+func (l *ListView) realize(width int) {
+	viewport := l.viewport()
+	_, scrollY := l.ScrollPosition()
+	first := l.indexAt(scrollY)
+	last := minInt(l.indexAt(scrollY+viewport.Dy()-1)+1, l.count)
+
+	for index, row := range l.rows {
+		if index < first || index >= last {
+			delete(l.rows, index)
+			l.body.RemoveChild(row)
+			l.pool = append(l.pool, row)
+		}
+	}
+
+	for index := first; index < last; index++ {
+		row, ok := l.rows[index]
+		if !ok {
+			if n := len(l.pool); n > 0 {
+				row, l.pool = l.pool[n-1], l.pool[:n-1]
+			} else {
+				row = l.delegate.CreateRow()
+			}
+			l.rows[index] = row
+			l.body.AddChild(row)
+			l.bind(row, index)
+		}
+		gui.ArrangeElement(row, image.Rect(0, l.rowTop(index), width, l.rowTop(index+1)))
+	}
+}
+
+// listBody is the tall element the scroll view moves, holding only the
+// rows of visible items
+type listBody struct {
+	*gui.Element
+	list *ListView
+}
+
+// Render draws the selection and the keyboard's row behind the rows
+func (b *listBody) Render(canvas gui.Canvas) error {
+	if !b.IsVisible() {
+		return nil
+	}
+
+	l := b.list
+	x, y, width, _ := b.GetBounds()
+	for index := range l.rows {
+		if l.selection.contains(index) {
+			top := l.rowTop(index)
+			if err := canvas.DrawRectangle(x, y+top, width, l.rowTop(index+1)-top, l.selectionColor, true); err != nil {
+				return err
+			}
+		}
+	}
+	if err := b.Element.Render(canvas); err != nil {
+		return err
+	}
+
+	if _, shown := l.rows[l.current]; shown && l.focused && l.mode != SelectionNone {
+		top := l.rowTop(l.current)
+		return canvas.DrawRectangle(x, y+top, width, l.rowTop(l.current+1)-top, l.currentColor, false)
+	}
+	return nil
+}
+
+// listLayout makes the body as tall as every row together and binds the
+// rows in view
+type listLayout struct {
+	list *ListView
+}
+
+// Measure asks for the height of all rows; the width is the viewport's
+// unless the rows are wider
+func (l listLayout) Measure(children []gui.GUIElement, constraints gui.Constraints) gui.Size {
+	return gui.Size{Width: maxInt(constraints.MinWidth, l.list.width), Height: l.list.rowTop(l.list.count)}
+}
+
+// Arrange binds and places the rows of the items in view
+func (l listLayout) Arrange(children []gui.GUIElement, area image.Rectangle) {
+	l.list.realize(area.Dx())
+}
+
+// TextRowDelegate shows items as a line of text, formatted with fmt.Sprint
+type TextRowDelegate struct {
+	Font  font.Face      // Defaults to basicfont.Face7x13
+	Color colorful.Color // Text colour, black by default
+}
+
+// CreateRow returns an empty text row
+func (d TextRowDelegate) CreateRow() gui.GUIElement {
+	face := d.Font
+	if face == nil {
+		face = basicfont.Face7x13
+	}
+	return &textRow{Element: gui.NewElement(0, 0, 0, 0), font: face, color: d.Color}
+}
+
+// BindRow sets the row's text to the item
+func (d TextRowDelegate) BindRow(row gui.GUIElement, index int, item interface{}, selected bool) {
+	if text, ok := row.(*textRow); ok {
+		text.setText(fmt.Sprint(item))
+	}
+}
+
+// textRow is a line of text centred vertically in a list row
+type textRow struct {
+	*gui.Element
+	text  string
+	font  font.Face
+	color colorful.Color
+}
+
+// setText changes the text, repainting only the row
+func (r *textRow) setText(text string) {
+	if text == r.text {
+		return
+	}
+	r.text = text
+	_, _, width, height := r.GetBounds()
+	r.InvalidateRect(0, 0, width, height)
+}
+
+// Render draws the text inset from the left edge
+func (r *textRow) Render(canvas gui.Canvas) error {
+	if !r.IsVisible() || r.text == "" {
+		return nil
+	}
+	x, y, _, height := r.GetBounds()
+	metrics := r.font.Metrics()
+	lineHeight := (metrics.Ascent + metrics.Descent).Ceil()
+	return canvas.DrawText(r.text, x+6, y+(height-lineHeight)/2+metrics.Ascent.Ceil(), r.font, r.color)
+}
+
+// indexRange is a half-open run of item indices
+type indexRange struct {
+	start, end int
+}
+
+// indexSet is a set of item indices kept as sorted, separate runs, so
+// selecting a million items takes one run
+type indexSet []indexRange
+
+// indexSetOf returns the set of sorted, distinct indices
+func indexSetOf(indices []int) indexSet {
+	var s indexSet
+	for _, index := range indices {
+		if last := len(s) - 1; last >= 0 && s[last].end == index {
+			s[last].end++
+		} else {
+			s = append(s, indexRange{index, index + 1})
+		}
+	}
+	return s
+}
+
+// contains reports whether the set holds an index
+func (s indexSet) contains(index int) bool {
+	i := sort.Search(len(s), func(i int) bool { return s[i].end > index })
+	return i < len(s) && s[i].start <= index
+}
+
+// add returns the set with the indices in [start, end) added
+func (s indexSet) add(start, end int) indexSet {
+	if start >= end {
+		return s
+	}
+	merged := make(indexSet, 0, len(s)+1)
+	i := 0
+	for ; i < len(s) && s[i].end < start; i++ {
+		merged = append(merged, s[i])
+	}
+	for ; i < len(s) && s[i].start <= end; i++ {
+		start, end = minInt(start, s[i].start), maxInt(end, s[i].end)
+	}
+	merged = append(merged, indexRange{start, end})
+	return append(merged, s[i:]...)
+}
+
+// remove returns the set without an index
+func (s indexSet) remove(index int) indexSet {
+	if !s.contains(index) {
+		return s
+	}
+	i := sort.Search(len(s), func(i int) bool { return s[i].end > index })
+	r := s[i]
+	split := append(indexSet{}, s[:i]...)
+	if r.start < index {
+		split = append(split, indexRange{r.start, index})
+	}
+	if index+1 < r.end {
+		split = append(split, indexRange{index + 1, r.end})
+	}
+	return append(split, s[i+1:]...)
+}
+
+// truncate returns the set without indices from n on
+func (s indexSet) truncate(n int) indexSet {
+	kept := append(indexSet{}, s...)
+	for len(kept) > 0 && kept[len(kept)-1].start >= n {
+		kept = kept[:len(kept)-1]
+	}
+	if last := len(kept) - 1; last >= 0 && kept[last].end > n {
+		kept[last].end = n
+	}
+	return kept
+}
+
+// first returns the lowest index, or -1 for an empty set
+func (s indexSet) first() int {
+	if len(s) == 0 {
+		return -1
+	}
+	return s[0].start
+}
+
+// count returns the number of indices
+func (s indexSet) count() int {
+	n := 0
+	for _, r := range s {
+		n += r.end - r.start
+	}
+	return n
+}
+
+// indices returns every index in order
+func (s indexSet) indices() []int {
+	indices := make([]int, 0, s.count())
+	for _, r := range s {
+		for i := r.start; i < r.end; i++ {
+			indices = append(indices, i)
+		}
+	}
+	return indices
+}
+
+// equal reports whether two sets hold the same indices
+func (s indexSet) equal(other indexSet) bool {
+	if len(s) != len(other) {
+		return false
+	}
+	for i := range s {
+		if s[i] != other[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,564 @@
+package components
+
+import (
+	"image"
+	"math"
+	"time"
+
+	"github.com/lucasb-eyer/go-colorful"
+	"github.com/opd-ai/gui"
+	"github.com/opd-ai/gui/gesture"
+)
+
+// Scrolling distances and feel
+const (
+	// ScrollbarWidth is the thickness of a scrollbar in pixels
+	ScrollbarWidth = 10
+	// MinThumbLength keeps scrollbar thumbs large enough to grab
+	MinThumbLength = 20
+	// ScrollLineStep is how far the arrow keys scroll
+	ScrollLineStep = 20
+	// WheelNotchStep is how far one notch of a mouse wheel scrolls
+	WheelNotchStep = 48
+	// ScrollFriction is how quickly a flick slows down, per second
+	ScrollFriction = 3.0
+	// minFlingSpeed is the speed, in pixels per second, a flick stops at
+	minFlingSpeed = 20.0
+)
+
+// ScrollbarPolicy controls when a scrollbar is shown
+type ScrollbarPolicy int
+
+const (
+	ScrollbarAuto   ScrollbarPolicy = iota // Shown when the content overflows
+	ScrollbarAlways                        // Always shown
+	ScrollbarNever                         // Never shown; the content fits the width or height
+)
+
+// scrollAxis identifies a scrollbar
+type scrollAxis int
+
+const (
+	axisNone scrollAxis = iota
+	axisHorizontal
+	axisVertical
+)
+
+// ScrollView shows a part of a larger content element, moving the content
+// to its scroll position and clipping it to the viewport. It scrolls with
+// scrollbars, the wheel, the keyboard and touch pans with inertia, and
+// scrolls focused descendants into view.
+type ScrollView struct {
+	*gui.Element
+	content gui.GUIElement
+
+	scrollX, scrollY int
+	hPolicy, vPolicy ScrollbarPolicy
+	showH, showV     bool // Scrollbars shown by the last layout
+
+	dragAxis   scrollAxis // Scrollbar whose thumb is held
+	dragGrab   int        // Pointer offset into the held thumb
+	fling      int        // Generation of the running fling; bumping it stops the fling
+	pan        *gesture.Pan
+	trackColor colorful.Color
+	thumbColor colorful.Color
+
+	onScroll func(x, y int)
+	scrolled func() // Lets components built on the view follow its position
+}
+
+// NewScrollView creates a scroll view around content, which may be nil
+func NewScrollView(content gui.GUIElement) *ScrollView {
+	s := &ScrollView{
+		Element:    gui.NewElement(0, 0, 200, 200),
+		trackColor: colorful.Color{R: 0.94, G: 0.94, B: 0.94}, // Very light gray
+		thumbColor: colorful.Color{R: 0.7, G: 0.7, B: 0.7},    // Gray
+	}
+	s.SetLayout(scrollLayout{s})
+	s.SetFocusable(true)
+	if content != nil {
+		s.SetContent(content)
+	}
+
+	s.AddCaptureHandler(gui.EventTypeMouseDown, gui.EventHandlerFunc(s.handleMouseDown))
+	s.AddEventHandler(gui.EventTypeMouseDrag, gui.EventHandlerFunc(s.handleMouseDrag))
+	s.AddEventHandler(gui.EventTypeMouseUp, gui.EventHandlerFunc(s.handleMouseUp))
+	s.AddEventHandler(gui.EventTypeWheel, gui.EventHandlerFunc(s.handleWheel))
+	s.AddEventHandler(gui.EventTypeKeyPress, gui.EventHandlerFunc(s.handleKeyPress))
+	// Focus is caught on the way down, since focusable children such as
+	// Input and Button stop it from bubbling
+	s.AddCaptureHandler(gui.EventTypeFocus, gui.EventHandlerFunc(s.handleFocus))
+
+	s.pan = gesture.NewPan(func(dx, dy int) {
+		s.ScrollBy(-dx, -dy)
+	})
+	s.pan.OnPanStart = func(x, y int) { s.stopFling() }
+	s.pan.OnPanEnd = func(vx, vy float64) { s.startFling(-vx, -vy) }
+	gesture.Attach(s, s.pan)
+
+	return s
+}
+
+// SetContent replaces the scrolled element and scrolls back to the top
+func (s *ScrollView) SetContent(content gui.GUIElement) *ScrollView {
+	if s.content != nil {
+		s.RemoveChild(s.content)
+	}
+	s.content = content
+	s.scrollX, s.scrollY = 0, 0
+	if content != nil {
+		s.AddChild(content)
+	}
+	return s
+}
+
+// Content returns the scrolled element
+func (s *ScrollView) Content() gui.GUIElement {
+	return s.content
+}
+
+// SetScrollbars sets when the horizontal and vertical scrollbars show
+func (s *ScrollView) SetScrollbars(horizontal, vertical ScrollbarPolicy) *ScrollView {
+	s.hPolicy, s.vPolicy = horizontal, vertical
+	s.InvalidateLayout()
+	return s
+}
+
+// SetScrollbarColors sets the colours of the scrollbar tracks and thumbs
+func (s *ScrollView) SetScrollbarColors(track, thumb colorful.Color) *ScrollView {
+	s.trackColor, s.thumbColor = track, thumb
+	s.invalidateScrollbars()
+	return s
+}
+
+// SetOnScroll sets a callback run whenever the scroll position changes
+func (s *ScrollView) SetOnScroll(callback func(x, y int)) *ScrollView {
+	s.onScroll = callback
+	return s
+}
+
+// ScrollPosition returns how far the content is scrolled
+func (s *ScrollView) ScrollPosition() (x, y int) {
+	return s.scrollX, s.scrollY
+}
+
+// MaxScroll returns the furthest the content can be scrolled
+func (s *ScrollView) MaxScroll() (x, y int) {
+	if s.content == nil {
+		return 0, 0
+	}
+	viewport := s.viewport()
+	_, _, width, height := s.content.GetBounds()
+	return maxInt(width-viewport.Dx(), 0), maxInt(height-viewport.Dy(), 0)
+}
+
+// ScrollTo scrolls the content so that x, y is at the viewport's top-left,
+// clamped to the content, and reports whether the position changed
+func (s *ScrollView) ScrollTo(x, y int) bool {
+	maxX, maxY := s.MaxScroll()
+	x, y = clampConstraint(x, 0, maxX), clampConstraint(y, 0, maxY)
+	if x == s.scrollX && y == s.scrollY {
+		return false
+	}
+	s.scrollX, s.scrollY = x, y
+	s.placeContent()
+	s.invalidateScrollbars()
+	if s.scrolled != nil {
+		s.scrolled()
+	}
+	if s.onScroll != nil {
+		s.onScroll(x, y)
+	}
+	return true
+}
+
+// ScrollBy scrolls the content by a distance, reporting whether it moved
+func (s *ScrollView) ScrollBy(dx, dy int) bool {
+	return s.ScrollTo(s.scrollX+dx, s.scrollY+dy)
+}
+
+// EnsureVisible scrolls as little as possible to bring a descendant fully
+// into the viewport, or its top-left part when it is larger
+func (s *ScrollView) EnsureVisible(el gui.GUIElement) {
+	mapper, ok := el.(interface{ LocalToWindow(x, y int) (int, int) })
+	if !ok || el == s.content || !s.IsAncestorOf(el) {
+		return
+	}
+
+	// The element's corners in our coordinates
+	_, _, width, height := el.GetBounds()
+	x0, y0 := s.WindowToLocal(mapper.LocalToWindow(0, 0))
+	x1, y1 := s.WindowToLocal(mapper.LocalToWindow(width, height))
+	target := image.Rect(x0, y0, x1, y1).Canon()
+	viewport := s.viewport()
+
+	x, y := s.scrollX, s.scrollY
+	switch {
+	case target.Min.X < viewport.Min.X || target.Dx() > viewport.Dx():
+		x += target.Min.X - viewport.Min.X
+	case target.Max.X > viewport.Max.X:
+		x += target.Max.X - viewport.Max.X
+	}
+	switch {
+	case target.Min.Y < viewport.Min.Y || target.Dy() > viewport.Dy():
+		y += target.Min.Y - viewport.Min.Y
+	case target.Max.Y > viewport.Max.Y:
+		y += target.Max.Y - viewport.Max.Y
+	}
+	s.ScrollTo(x, y)
+}
+
+// Render draws the content clipped to the viewport, then the scrollbars
+func (s *ScrollView) Render(canvas gui.Canvas) error {
+	if !s.IsVisible() {
+		return nil
+	}
+
+	x, y, _, _ := s.GetBounds()
+	viewport := s.viewport()
+	canvas.Push()
+	canvas.SetClippingRegion(x+viewport.Min.X, y+viewport.Min.Y, viewport.Dx(), viewport.Dy())
+	err := s.Element.Render(canvas)
+	canvas.Pop()
+	if err != nil {
+		return err
+	}
+
+	for _, axis := range []scrollAxis{axisHorizontal, axisVertical} {
+		track, thumb := s.scrollbar(axis)
+		if track.Empty() {
+			continue
+		}
+		if err := canvas.DrawRectangle(x+track.Min.X, y+track.Min.Y, track.Dx(), track.Dy(), s.trackColor, true); err != nil {
+			return err
+		}
+		if err := canvas.DrawRectangle(x+thumb.Min.X, y+thumb.Min.Y, thumb.Dx(), thumb.Dy(), s.thumbColor, true); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// HitClip limits hit testing of the content to the viewport, so padding,
+// the scrollbars and the corner between them belong to the scroll view
+func (s *ScrollView) HitClip() image.Rectangle {
+	return s.viewport()
+}
+
+// viewport returns the visible content area in local coordinates: the
+// padded area less the scrollbars
+func (s *ScrollView) viewport() image.Rectangle {
+	_, _, width, height := s.GetBounds()
+	padding := s.Padding()
+	r := image.Rect(padding.Left, padding.Top, width-padding.Right, height-padding.Bottom)
+	if s.showV {
+		r.Max.X -= ScrollbarWidth
+	}
+	if s.showH {
+		r.Max.Y -= ScrollbarWidth
+	}
+	if r.Dx() < 0 || r.Dy() < 0 {
+		r.Max = r.Min
+	}
+	return r
+}
+
+// scrollbar returns the track and thumb of a scrollbar in local
+// coordinates, both empty when it is hidden
+func (s *ScrollView) scrollbar(axis scrollAxis) (track, thumb image.Rectangle) {
+	viewport := s.viewport()
+	maxX, maxY := s.MaxScroll()
+	if axis == axisVertical {
+		if !s.showV {
+			return image.Rectangle{}, image.Rectangle{}
+		}
+		track = image.Rect(viewport.Max.X, viewport.Min.Y, viewport.Max.X+ScrollbarWidth, viewport.Max.Y)
+		start, length := thumbSpan(track.Dy(), viewport.Dy(), s.scrollY, maxY)
+		return track, image.Rect(track.Min.X, track.Min.Y+start, track.Max.X, track.Min.Y+start+length)
+	}
+	if !s.showH {
+		return image.Rectangle{}, image.Rectangle{}
+	}
+	track = image.Rect(viewport.Min.X, viewport.Max.Y, viewport.Max.X, viewport.Max.Y+ScrollbarWidth)
+	start, length := thumbSpan(track.Dx(), viewport.Dx(), s.scrollX, maxX)
+	return track, image.Rect(track.Min.X+start, track.Min.Y, track.Min.X+start+length, track.Max.Y)
+}
+
+// thumbSpan returns where a thumb starts along its track and how long it
+// is, in proportion to the visible part of the content
+func thumbSpan(track, visible, scroll, maxScroll int) (start, length int) {
+	if maxScroll <= 0 || track <= 0 {
+		return 0, track
+	}
+	length = track * visible / (visible + maxScroll)
+	if length < MinThumbLength {
+		length = MinThumbLength
+	}
+	if length > track {
+		length = track
+	}
+	return (track - length) * scroll / maxScroll, length
+}
+
+// placeContent moves the content to the scroll position
+func (s *ScrollView) placeContent() {
+	if s.content == nil {
+		return
+	}
+	viewport := s.viewport()
+	s.content.SetPosition(viewport.Min.X-s.scrollX, viewport.Min.Y-s.scrollY)
+}
+
+// invalidateScrollbars repaints both scrollbars
+func (s *ScrollView) invalidateScrollbars() {
+	for _, axis := range []scrollAxis{axisHorizontal, axisVertical} {
+		if track, _ := s.scrollbar(axis); !track.Empty() {
+			s.InvalidateRect(track.Min.X, track.Min.Y, track.Dx(), track.Dy())
+		}
+	}
+}
+
+// handleMouseDown grabs a scrollbar thumb, or pages towards the pointer
+// when the track is pressed; it runs before the content sees the press
+func (s *ScrollView) handleMouseDown(event gui.Event) bool {
+	down := event.(*gui.MouseDownEvent)
+	if down.Button != gui.MouseButtonLeft {
+		return false
+	}
+	point := image.Pt(down.LocalX, down.LocalY)
+
+	for _, axis := range []scrollAxis{axisHorizontal, axisVertical} {
+		track, thumb := s.scrollbar(axis)
+		if !point.In(track) {
+			continue
+		}
+		s.stopFling()
+		if point.In(thumb) {
+			s.dragAxis = axis
+			s.dragGrab = point.Y - thumb.Min.Y
+			if axis == axisHorizontal {
+				s.dragGrab = point.X - thumb.Min.X
+			}
+			if w := s.Window(); w != nil {
+				w.CapturePointer(s)
+			}
+			return true
+		}
+
+		// A press on the track pages towards the pointer
+		viewport := s.viewport()
+		switch {
+		case axis == axisVertical && point.Y < thumb.Min.Y:
+			s.ScrollBy(0, -pageStep(viewport.Dy()))
+		case axis == axisVertical:
+			s.ScrollBy(0, pageStep(viewport.Dy()))
+		case point.X < thumb.Min.X:
+			s.ScrollBy(-pageStep(viewport.Dx()), 0)
+		default:
+			s.ScrollBy(pageStep(viewport.Dx()), 0)
+		}
+		return true
+	}
+	return false
+}
+
+// handleMouseDrag moves a held thumb with the pointer
+func (s *ScrollView) handleMouseDrag(event gui.Event) bool {
+	if s.dragAxis == axisNone {
+		return false
+	}
+	drag := event.(*gui.MouseDragEvent)
+	track, thumb := s.scrollbar(s.dragAxis)
+	maxX, maxY := s.MaxScroll()
+
+	if s.dragAxis == axisVertical {
+		free := track.Dy() - thumb.Dy()
+		if free > 0 {
+			s.ScrollTo(s.scrollX, (drag.LocalY-s.dragGrab-track.Min.Y)*maxY/free)
+		}
+	} else {
+		free := track.Dx() - thumb.Dx()
+		if free > 0 {
+			s.ScrollTo((drag.LocalX-s.dragGrab-track.Min.X)*maxX/free, s.scrollY)
+		}
+	}
+	return true
+}
+
+// handleMouseUp lets go of a held thumb without clicking what lies under it
+func (s *ScrollView) handleMouseUp(event gui.Event) bool {
+	if s.dragAxis == axisNone {
+		return false
+	}
+	s.dragAxis = axisNone
+	event.PreventDefault()
+	return true
+}
+
+// handleWheel scrolls by wheel notches or touchpad pixels; Shift turns
+// vertical wheel movement horizontal. Unused wheel movement bubbles on, so
+// nested scroll views hand over at their ends.
+func (s *ScrollView) handleWheel(event gui.Event) bool {
+	wheel := event.(*gui.WheelEvent)
+	dx, dy := wheel.DeltaX, wheel.DeltaY
+	if wheel.Modifiers.Has(gui.ModifierShift) && dx == 0 {
+		dx, dy = dy, 0
+	}
+	if wheel.Unit == gui.WheelUnitLine {
+		dx, dy = dx*WheelNotchStep, dy*WheelNotchStep
+	}
+	s.stopFling()
+	return s.ScrollBy(int(math.Round(dx)), int(math.Round(dy)))
+}
+
+// handleKeyPress scrolls with the arrow keys, PageUp, PageDown, Home and
+// End when the focused element leaves them unused
+func (s *ScrollView) handleKeyPress(event gui.Event) bool {
+	if event.IsDefaultPrevented() {
+		return false
+	}
+	key := event.(*gui.KeyPressEvent)
+	viewport := s.viewport()
+	_, maxY := s.MaxScroll()
+
+	// Home and End belong to a focused descendant, such as an Input,
+	// unless Ctrl is held
+	ownKeys := key.Target() == s || key.Modifiers.Has(gui.ModifierCtrl)
+
+	s.stopFling()
+	switch key.Key {
+	case gui.KeyArrowUp:
+		return s.ScrollBy(0, -ScrollLineStep)
+	case gui.KeyArrowDown:
+		return s.ScrollBy(0, ScrollLineStep)
+	case gui.KeyArrowLeft:
+		return s.ScrollBy(-ScrollLineStep, 0)
+	case gui.KeyArrowRight:
+		return s.ScrollBy(ScrollLineStep, 0)
+	case gui.KeyPageUp:
+		return s.ScrollBy(0, -pageStep(viewport.Dy()))
+	case gui.KeyPageDown:
+		return s.ScrollBy(0, pageStep(viewport.Dy()))
+	case gui.KeyHome:
+		if ownKeys {
+			return s.ScrollTo(s.scrollX, 0)
+		}
+	case gui.KeyEnd:
+		if ownKeys {
+			return s.ScrollTo(s.scrollX, maxY)
+		}
+	}
+	return false
+}
+
+// handleFocus scrolls a descendant that gains focus into view
+func (s *ScrollView) handleFocus(event gui.Event) bool {
+	if target, ok := event.(interface{ Target() gui.GUIElement }); ok {
+		if el := target.Target(); el != nil && el != s {
+			s.EnsureVisible(el)
+		}
+	}
+	return false
+}
+
+// pageStep returns how far a page scrolls, keeping a line of overlap
+func pageStep(visible int) int {
+	return maxInt(visible-ScrollLineStep, ScrollLineStep)
+}
+
+// startFling keeps scrolling after a flick, slowing down with friction
+func (s *ScrollView) startFling(vx, vy float64) {
+	s.fling++
+	generation := s.fling
+	last := time.Now()
+
+	s.Animate(gui.FrameFunc(func(now time.Time) bool {
+		if s.fling != generation {
+			return false
+		}
+		dt := now.Sub(last).Seconds()
+		last = now
+		if dt <= 0 {
+			return true
+		}
+
+		moved := s.ScrollBy(int(math.Round(vx*dt)), int(math.Round(vy*dt)))
+		decay := math.Exp(-ScrollFriction * dt)
+		vx, vy = vx*decay, vy*decay
+		return moved && math.Hypot(vx, vy) >= minFlingSpeed
+	}))
+}
+
+// stopFling ends a running fling
+func (s *ScrollView) stopFling() {
+	s.fling++
+}
+
+// scrollLayout sizes the content to the viewport where it cannot scroll
+// and to its measured size where it can, then decides which scrollbars show
+type scrollLayout struct {
+	view *ScrollView
+}
+
+// Measure asks for the content's size plus any scrollbars always shown
+func (l scrollLayout) Measure(children []gui.GUIElement, constraints gui.Constraints) gui.Size {
+	var size gui.Size
+	for _, child := range children {
+		size = gui.MeasureElement(child, gui.UnboundedConstraints())
+	}
+	if l.view.vPolicy == ScrollbarAlways {
+		size.Width += ScrollbarWidth
+	}
+	if l.view.hPolicy == ScrollbarAlways {
+		size.Height += ScrollbarWidth
+	}
+	return size
+}
+
+// Arrange measures the content against the viewport, showing a scrollbar
+// for each axis it overflows; showing one shrinks the viewport, so it
+// checks again
+func (l scrollLayout) Arrange(children []gui.GUIElement, area image.Rectangle) {
+	s := l.view
+	if s.content == nil || !s.content.IsVisible() {
+		s.showH, s.showV = s.hPolicy == ScrollbarAlways, s.vPolicy == ScrollbarAlways
+		return
+	}
+
+	s.showH, s.showV = s.hPolicy == ScrollbarAlways, s.vPolicy == ScrollbarAlways
+	var size gui.Size
+	for pass := 0; pass < 3; pass++ {
+		viewport := s.viewport()
+		maxWidth := gui.Unbounded
+		if s.hPolicy == ScrollbarNever {
+			maxWidth = viewport.Dx()
+		}
+		maxHeight := gui.Unbounded
+		if s.vPolicy == ScrollbarNever {
+			maxHeight = viewport.Dy()
+		}
+		size = gui.MeasureElement(s.content, gui.Constraints{MaxWidth: maxWidth, MaxHeight: maxHeight})
+		size.Width = maxInt(size.Width, viewport.Dx())
+		size.Height = maxInt(size.Height, viewport.Dy())
+
+		showH := s.hPolicy == ScrollbarAlways || (s.hPolicy == ScrollbarAuto && size.Width > viewport.Dx())
+		showV := s.vPolicy == ScrollbarAlways || (s.vPolicy == ScrollbarAuto && size.Height > viewport.Dy())
+		if showH == s.showH && showV == s.showV {
+			break
+		}
+		s.showH, s.showV = showH, showV
+	}
+
+	viewport := s.viewport()
+	gui.ArrangeElement(s.content, image.Rect(0, 0, size.Width, size.Height).Add(viewport.Min))
+
+	// A smaller content or larger viewport can leave the scroll position
+	// past the end
+	maxX, maxY := s.MaxScroll()
+	s.scrollX, s.scrollY = clampConstraint(s.scrollX, 0, maxX), clampConstraint(s.scrollY, 0, maxY)
+	s.placeContent()
+
+	// Scrollbars may have come or gone; repaint without asking for layout again
+	_, _, width, height := s.GetBounds()
+	s.InvalidateRect(0, 0, width, height)
+}
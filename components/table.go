@@ -0,0 +1,1025 @@
+package components
+
+import (
+	"fmt"
+	"image"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lucasb-eyer/go-colorful"
+	"github.com/opd-ai/gui"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+)
+
+// Table sizes
+const (
+	// DefaultColumnWidth is the width of columns defined without one
+	DefaultColumnWidth = 100
+	// MinColumnWidth is the narrowest a column can be dragged by default
+	MinColumnWidth = 24
+	// DefaultHeaderHeight is the height of a table's header row
+	DefaultHeaderHeight = 24
+	// CellPadding is the space between a cell's edges and its text
+	CellPadding = 6
+	// gripWidth is the width of the grab area over a column divider
+	gripWidth = 6
+)
+
+// TableDataSource supplies the cells of a Table by row and column
+type TableDataSource interface {
+	// RowCount returns the number of rows
+	RowCount() int
+
+	// Value returns the value of a cell, which a CellRenderer draws
+	Value(row, column int) interface{}
+}
+
+// TableEditor is implemented by data sources whose cells can be edited;
+// SetValue receives the text typed into the cell and an error rejects it
+type TableEditor interface {
+	SetValue(row, column int, text string) error
+}
+
+// CellRenderer draws the value of a table cell within bounds, which are in
+// the canvas's current coordinates
+type CellRenderer interface {
+	RenderCell(canvas gui.Canvas, bounds image.Rectangle, value interface{}, column TableColumn, selected bool) error
+}
+
+// CellRendererFunc adapts a function to a CellRenderer
+type CellRendererFunc func(canvas gui.Canvas, bounds image.Rectangle, value interface{}, column TableColumn, selected bool) error
+
+// RenderCell calls f
+func (f CellRendererFunc) RenderCell(canvas gui.Canvas, bounds image.Rectangle, value interface{}, column TableColumn, selected bool) error {
+	return f(canvas, bounds, value, column, selected)
+}
+
+// TableColumn defines a column of a Table
+type TableColumn struct {
+	Header    string
+	Width     int                         // Starting width; zero uses DefaultColumnWidth
+	MinWidth  int                         // Narrowest a drag can make it; zero uses MinColumnWidth
+	Alignment TextAlignment               // Of the cell and header text
+	Renderer  CellRenderer                // Draws the cells; nil draws values as text
+	Sortable  bool                        // Clicking the header sorts by the column
+	Less      func(a, b interface{}) bool // Orders values when sorting; nil compares numbers, strings and times
+	Editable  bool                        // Double-click or F2 edits the cell, when the source is a TableEditor
+}
+
+// TextCellRenderer draws values as a line of text, aligned as the column
+// says and shortened with an ellipsis when too wide
+type TextCellRenderer struct {
+	Font   font.Face                      // Defaults to basicfont.Face7x13
+	Color  colorful.Color                 // Black by default
+	Format func(value interface{}) string // Defaults to fmt.Sprint, with nil shown empty
+}
+
+// RenderCell draws the value's text
+func (r TextCellRenderer) RenderCell(canvas gui.Canvas, bounds image.Rectangle, value interface{}, column TableColumn, selected bool) error {
+	text := cellText(value)
+	if r.Format != nil {
+		text = r.Format(value)
+	}
+	return drawCellText(canvas, bounds, text, column.Alignment, r.Font, r.Color)
+}
+
+// TableRows is a TableDataSource over rows of values. It is also a
+// TableEditor: typed text is parsed to the type of the value it replaces
+// where that is a number or a bool, and stored as a string otherwise.
+type TableRows [][]interface{}
+
+// RowCount returns the number of rows
+func (r TableRows) RowCount() int {
+	return len(r)
+}
+
+// Value returns a cell, nil past the end of a short row
+func (r TableRows) Value(row, column int) interface{} {
+	if column >= len(r[row]) {
+		return nil
+	}
+	return r[row][column]
+}
+
+// SetValue stores edited text in a cell
+func (r TableRows) SetValue(row, column int, text string) error {
+	if column >= len(r[row]) {
+		return fmt.Errorf("row %d has no column %d", row, column)
+	}
+	value, err := parseLike(r[row][column], text)
+	if err != nil {
+		return err
+	}
+	r[row][column] = value
+	return nil
+}
+
+// Table shows rows of a data source in resizable columns under a header
+// that stays in place as the rows scroll. Clicking a sortable header sorts
+// the rows, dragging a divider resizes a column and editable cells open
+// in an Input. Rows are virtualized by a ListView, so large sources cost
+// no more than small ones. Row indices in the API are those of the data
+// source, whatever the sort order.
+type Table struct {
+	*gui.Element
+	header *tableHeader
+	list   *ListView
+	editor *Input
+	grips  []*columnGrip
+
+	columns      []TableColumn
+	source       TableDataSource
+	order        []int // Source row shown at each position, nil while unsorted
+	position     []int // Position of each source row, nil while unsorted
+	sortColumn   int   // -1 while unsorted
+	ascending    bool
+	headerHeight int
+
+	editRow, editColumn int // Cell being edited; editColumn is -1 when none is
+
+	headerFont  font.Face
+	headerColor colorful.Color
+	gridColor   colorful.Color
+
+	onSort             func(column int, ascending bool)
+	onActivate         func(row int)
+	onSelectionChanged func()
+	onEditError        func(row, column int, err error)
+}
+
+// NewTable creates a table of the rows in source with the given columns
+func NewTable(source TableDataSource, columns ...TableColumn) *Table {
+	t := &Table{
+		Element:      gui.NewElement(0, 0, 400, 300),
+		sortColumn:   -1,
+		editColumn:   -1,
+		headerHeight: DefaultHeaderHeight,
+		headerFont:   basicfont.Face7x13,
+		headerColor:  colorful.Color{R: 0.93, G: 0.93, B: 0.93}, // Light gray
+		gridColor:    colorful.Color{R: 0.85, G: 0.85, B: 0.85}, // Gray
+	}
+
+	t.header = &tableHeader{Element: gui.NewElement(0, 0, 0, 0), table: t}
+	t.header.SetLayout(headerLayout{t})
+	t.header.AddEventHandler(gui.EventTypeClick, gui.EventHandlerFunc(t.handleHeaderClick))
+
+	t.list = NewListView(nil)
+	t.list.SetRowDelegate(tableRowDelegate{t})
+	t.list.SetScrollbars(ScrollbarAuto, ScrollbarAuto)
+	t.list.SetOnScroll(t.handleScroll)
+	t.list.SetOnSelectionChanged(func() {
+		if t.onSelectionChanged != nil {
+			t.onSelectionChanged()
+		}
+	})
+	t.list.SetOnActivate(func(index int) {
+		if t.onActivate != nil {
+			t.onActivate(t.sourceRow(index))
+		}
+	})
+
+	t.editor = NewInput()
+	t.editor.SetVisible(false)
+	t.editor.SetOnSubmit(func(string) { t.commitEdit() })
+	t.editor.SetOnBlur(t.commitOrCancel)
+
+	t.AddChild(t.header)
+	t.AddChild(t.list)
+	t.AddChild(t.editor)
+	t.SetLayout(tableLayout{t})
+	t.AddEventHandler(gui.EventTypeKeyPress, gui.EventHandlerFunc(t.handleKeyPress))
+	t.AddCaptureHandler(gui.EventTypeDoubleClick, gui.EventHandlerFunc(t.handleDoubleClick))
+
+	t.SetColumns(columns...)
+	t.SetDataSource(source)
+	return t
+}
+
+// SetColumns replaces the columns, clearing the sort order
+func (t *Table) SetColumns(columns ...TableColumn) *Table {
+	t.CancelEdit()
+	t.columns = append([]TableColumn(nil), columns...)
+	for i := range t.columns {
+		if t.columns[i].Width <= 0 {
+			t.columns[i].Width = DefaultColumnWidth
+		}
+	}
+
+	for _, grip := range t.grips {
+		t.header.RemoveChild(grip)
+	}
+	t.grips = t.grips[:0]
+	for i := range t.columns {
+		grip := newColumnGrip(t, i)
+		t.grips = append(t.grips, grip)
+		t.header.AddChild(grip)
+	}
+
+	if t.sortColumn >= 0 {
+		t.sortColumn, t.order, t.position = -1, nil, nil
+		t.list.Reload()
+	}
+	t.list.width = t.totalWidth()
+	t.Invalidate()
+	t.InvalidateLayout()
+	return t
+}
+
+// Columns returns a copy of the column definitions, with current widths
+func (t *Table) Columns() []TableColumn {
+	return append([]TableColumn(nil), t.columns...)
+}
+
+// SetColumnWidth resizes a column, no narrower than its minimum
+func (t *Table) SetColumnWidth(column, width int) *Table {
+	if column < 0 || column >= len(t.columns) {
+		return t
+	}
+	min := t.columns[column].MinWidth
+	if min <= 0 {
+		min = MinColumnWidth
+	}
+	width = maxInt(width, min)
+	if width == t.columns[column].Width {
+		return t
+	}
+	t.columns[column].Width = width
+	t.list.width = t.totalWidth()
+	t.Invalidate()
+	t.InvalidateLayout()
+	return t
+}
+
+// ColumnWidth returns a column's current width
+func (t *Table) ColumnWidth(column int) int {
+	if column < 0 || column >= len(t.columns) {
+		return 0
+	}
+	return t.columns[column].Width
+}
+
+// SetDataSource replaces the rows, keeping the sort column, clearing the
+// selection and scrolling back to the top
+func (t *Table) SetDataSource(source TableDataSource) *Table {
+	t.CancelEdit()
+	t.source = source
+	t.order, t.position = nil, nil
+	if t.sortColumn >= 0 {
+		t.sort()
+	}
+	t.list.SetDataSource(tableItems{t})
+	return t
+}
+
+// DataSource returns the table's data source
+func (t *Table) DataSource() TableDataSource {
+	return t.source
+}
+
+// Reload picks up changes to the data source, sorting again if sorted
+func (t *Table) Reload() {
+	t.CancelEdit()
+	if t.sortColumn >= 0 {
+		t.SortBy(t.sortColumn, t.ascending)
+		return
+	}
+	t.list.Reload()
+}
+
+// SetHeaderHeight sets the height of the header row
+func (t *Table) SetHeaderHeight(height int) *Table {
+	t.headerHeight = maxInt(height, 0)
+	t.InvalidateLayout()
+	return t
+}
+
+// SetRowHeight sets the height of every row
+func (t *Table) SetRowHeight(height int) *Table {
+	t.list.SetRowHeight(height)
+	return t
+}
+
+// SetHeaderFont sets the font of the header text
+func (t *Table) SetHeaderFont(face font.Face) *Table {
+	t.headerFont = face
+	t.header.Invalidate()
+	return t
+}
+
+// SetColors sets the background of the header and the colour of the lines
+// between cells
+func (t *Table) SetColors(header, grid colorful.Color) *Table {
+	t.headerColor, t.gridColor = header, grid
+	t.Invalidate()
+	return t
+}
+
+// SetSelectionColors sets the background of selected rows and the outline
+// of the keyboard's row while the table has focus
+func (t *Table) SetSelectionColors(selection, current colorful.Color) *Table {
+	t.list.SetSelectionColors(selection, current)
+	return t
+}
+
+// SetOnSort sets a callback run after the rows are sorted
+func (t *Table) SetOnSort(callback func(column int, ascending bool)) *Table {
+	t.onSort = callback
+	return t
+}
+
+// SetOnActivate sets a callback run when a row is double-clicked, outside
+// an editable cell, or Enter is pressed on it
+func (t *Table) SetOnActivate(callback func(row int)) *Table {
+	t.onActivate = callback
+	return t
+}
+
+// SetOnSelectionChanged sets a callback run whenever the selection changes
+func (t *Table) SetOnSelectionChanged(callback func()) *Table {
+	t.onSelectionChanged = callback
+	return t
+}
+
+// SetOnEditError sets a callback run when the data source rejects an
+// edit; the editor stays open after Enter and closes otherwise
+func (t *Table) SetOnEditError(callback func(row, column int, err error)) *Table {
+	t.onEditError = callback
+	return t
+}
+
+// SortBy sorts the rows by a column's values, keeping the selection
+func (t *Table) SortBy(column int, ascending bool) {
+	if column < 0 || column >= len(t.columns) {
+		return
+	}
+	t.commitOrCancel()
+
+	selected := t.SelectedRows()
+	current := -1
+	if t.list.Count() > 0 {
+		current = t.sourceRow(t.list.CurrentIndex())
+	}
+
+	t.sortColumn, t.ascending = column, ascending
+	t.sort()
+
+	// Select the same rows at their new positions
+	positions := make([]int, 0, len(selected))
+	for _, row := range selected {
+		if row < len(t.position) {
+			positions = append(positions, t.position[row])
+		}
+	}
+	sort.Ints(positions)
+	t.list.selection = indexSetOf(positions)
+	if current >= 0 && current < len(t.position) {
+		t.list.current, t.list.anchor = t.position[current], t.position[current]
+	}
+	t.list.Reload()
+	t.header.Invalidate()
+
+	if t.onSort != nil {
+		t.onSort(column, ascending)
+	}
+}
+
+// ClearSort shows the rows in the data source's order again
+func (t *Table) ClearSort() {
+	if t.sortColumn < 0 {
+		return
+	}
+	selected := t.SelectedRows()
+	sort.Ints(selected)
+	t.sortColumn, t.order, t.position = -1, nil, nil
+	t.list.selection = indexSetOf(selected)
+	t.list.Reload()
+	t.header.Invalidate()
+}
+
+// SortColumn returns the column the rows are sorted by, or -1, and the
+// direction
+func (t *Table) SortColumn() (column int, ascending bool) {
+	return t.sortColumn, t.ascending
+}
+
+// SetSelectionMode sets how many rows can be selected
+func (t *Table) SetSelectionMode(mode SelectionMode) *Table {
+	t.list.SetSelectionMode(mode)
+	return t
+}
+
+// SelectedRow returns the first selected row in display order, or -1
+func (t *Table) SelectedRow() int {
+	index := t.list.SelectedIndex()
+	if index < 0 {
+		return -1
+	}
+	return t.sourceRow(index)
+}
+
+// SelectedRows returns the selected rows in display order
+func (t *Table) SelectedRows() []int {
+	rows := t.list.SelectedIndices()
+	for i, index := range rows {
+		rows[i] = t.sourceRow(index)
+	}
+	return rows
+}
+
+// IsRowSelected reports whether a row is selected
+func (t *Table) IsRowSelected(row int) bool {
+	return row >= 0 && row < t.list.Count() && t.list.IsSelected(t.displayIndex(row))
+}
+
+// SelectRow selects only a row and scrolls it into view
+func (t *Table) SelectRow(row int) {
+	if row < 0 || row >= t.list.Count() {
+		return
+	}
+	t.list.Select(t.displayIndex(row))
+	t.list.ScrollToIndex(t.displayIndex(row))
+}
+
+// ClearSelection deselects every row
+func (t *Table) ClearSelection() {
+	t.list.ClearSelection()
+}
+
+// CellAt returns the row and column under a point in the table's
+// coordinates, or -1 for each outside the cells
+func (t *Table) CellAt(x, y int) (row, column int) {
+	lx, ly, _, _ := t.list.GetBounds()
+	index := t.list.RowAt(x-lx, y-ly)
+	if index < 0 {
+		return -1, -1
+	}
+	scrollX, _ := t.list.ScrollPosition()
+	column = t.columnAt(x - lx - t.list.viewport().Min.X + scrollX)
+	if column < 0 {
+		return -1, -1
+	}
+	return t.sourceRow(index), column
+}
+
+// EditCell opens an Input over a cell of an editable column, reporting
+// whether it did; Enter or leaving the Input stores the text and Escape
+// discards it
+func (t *Table) EditCell(row, column int) bool {
+	if column < 0 || column >= len(t.columns) || !t.columns[column].Editable || row < 0 || row >= t.list.Count() {
+		return false
+	}
+	if _, ok := t.source.(TableEditor); !ok {
+		return false
+	}
+	t.commitOrCancel()
+
+	t.scrollToCell(t.displayIndex(row), column)
+	t.editRow, t.editColumn = row, column
+	t.editor.SetText(cellText(t.source.Value(row, column)))
+	t.editor.SetVisible(true)
+	t.placeEditor()
+	if w := t.Window(); w != nil {
+		w.FocusManager().SetFocus(t.editor)
+	}
+	return true
+}
+
+// IsEditing reports whether a cell is being edited
+func (t *Table) IsEditing() bool {
+	return t.editColumn >= 0
+}
+
+// CancelEdit closes the cell editor without storing its text
+func (t *Table) CancelEdit() {
+	if t.IsEditing() {
+		t.endEdit()
+	}
+}
+
+// commitEdit stores the editor's text in the data source and closes the
+// editor, reporting whether the source accepted it
+func (t *Table) commitEdit() bool {
+	if !t.IsEditing() {
+		return true
+	}
+	row, column := t.editRow, t.editColumn
+	if editor, ok := t.source.(TableEditor); ok {
+		if err := editor.SetValue(row, column, t.editor.GetText()); err != nil {
+			if t.onEditError != nil {
+				t.onEditError(row, column, err)
+			}
+			return false
+		}
+	}
+	t.endEdit()
+	t.list.Reload()
+	return true
+}
+
+// commitOrCancel stores the edited text, discarding it when rejected
+func (t *Table) commitOrCancel() {
+	if !t.commitEdit() {
+		t.CancelEdit()
+	}
+}
+
+// endEdit hides the editor, handing focus back to the rows
+func (t *Table) endEdit() {
+	t.editColumn = -1
+	t.editor.SetVisible(false)
+	if w := t.Window(); w != nil && w.FocusManager().Focused() == t.editor {
+		w.FocusManager().SetFocus(t.list)
+	}
+}
+
+// placeEditor moves the editor over the cell being edited
+func (t *Table) placeEditor() {
+	if !t.IsEditing() {
+		return
+	}
+	lx, ly, _, _ := t.list.GetBounds()
+	viewport := t.list.viewport()
+	scrollX, scrollY := t.list.ScrollPosition()
+	index := t.displayIndex(t.editRow)
+	left := lx + viewport.Min.X + t.columnLeft(t.editColumn) - scrollX
+	top := ly + viewport.Min.Y + t.list.rowTop(index) - scrollY
+	bottom := ly + viewport.Min.Y + t.list.rowTop(index+1) - scrollY
+	gui.ArrangeElement(t.editor, image.Rect(left, top, left+t.columns[t.editColumn].Width, bottom))
+}
+
+// scrollToCell scrolls as little as possible to show a cell
+func (t *Table) scrollToCell(index, column int) {
+	t.list.ScrollToIndex(index)
+	left := t.columnLeft(column)
+	right := left + t.columns[column].Width
+	viewport := t.list.viewport()
+	x, y := t.list.ScrollPosition()
+	switch {
+	case left < x || right-left > viewport.Dx():
+		x = left
+	case right > x+viewport.Dx():
+		x = right - viewport.Dx()
+	}
+	t.list.ScrollTo(x, y)
+}
+
+// sort orders the rows by the sort column
+func (t *Table) sort() {
+	count := 0
+	if t.source != nil {
+		count = t.source.RowCount()
+	}
+	column := t.sortColumn
+	less := t.columns[column].Less
+	if less == nil {
+		less = func(a, b interface{}) bool { return compareValues(a, b) < 0 }
+	}
+
+	order := make([]int, count)
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		a, b := t.source.Value(order[i], column), t.source.Value(order[j], column)
+		if t.ascending {
+			return less(a, b)
+		}
+		return less(b, a)
+	})
+
+	position := make([]int, count)
+	for index, row := range order {
+		position[row] = index
+	}
+	t.order, t.position = order, position
+}
+
+// sourceRow returns the source row shown at a position
+func (t *Table) sourceRow(index int) int {
+	if t.order == nil {
+		return index
+	}
+	return t.order[index]
+}
+
+// displayIndex returns where a source row is shown
+func (t *Table) displayIndex(row int) int {
+	if t.position == nil {
+		return row
+	}
+	return t.position[row]
+}
+
+// totalWidth returns the width of all columns together
+func (t *Table) totalWidth() int {
+	return t.columnLeft(len(t.columns))
+}
+
+// columnLeft returns where a column starts within the rows
+func (t *Table) columnLeft(column int) int {
+	left := 0
+	for i := 0; i < column && i < len(t.columns); i++ {
+		left += t.columns[i].Width
+	}
+	return left
+}
+
+// columnAt returns the column spanning a position within the rows, or -1
+func (t *Table) columnAt(x int) int {
+	if x < 0 {
+		return -1
+	}
+	for i, column := range t.columns {
+		if x < column.Width {
+			return i
+		}
+		x -= column.Width
+	}
+	return -1
+}
+
+// handleScroll keeps the header over its columns; the editor is closed
+// rather than followed
+func (t *Table) handleScroll(x, y int) {
+	t.commitOrCancel()
+	t.header.Invalidate()
+	t.header.InvalidateLayout() // Grips follow the columns
+}
+
+// handleHeaderClick sorts by a sortable column, reversing the order when
+// it is already sorted by it
+func (t *Table) handleHeaderClick(event gui.Event) bool {
+	click := event.(*gui.ClickEvent)
+	scrollX, _ := t.list.ScrollPosition()
+	column := t.columnAt(click.LocalX + scrollX)
+	if click.Button != gui.MouseButtonLeft || column < 0 || !t.columns[column].Sortable {
+		return false
+	}
+	t.SortBy(column, !(t.sortColumn == column && t.ascending))
+	return true
+}
+
+// handleDoubleClick edits an editable cell; it runs before the rows see
+// the double-click, which would activate the row
+func (t *Table) handleDoubleClick(event gui.Event) bool {
+	click := event.(*gui.DoubleClickEvent)
+	if click.Button != gui.MouseButtonLeft || click.Target() == t.editor {
+		return false
+	}
+	row, column := t.CellAt(click.LocalX, click.LocalY)
+	if row < 0 || !t.columns[column].Editable {
+		return false
+	}
+	return t.EditCell(row, column)
+}
+
+// handleKeyPress edits the keyboard's row with F2 and cancels editing
+// with Escape
+func (t *Table) handleKeyPress(event gui.Event) bool {
+	key := event.(*gui.KeyPressEvent)
+	switch {
+	case key.Key == gui.KeyEscape && t.IsEditing():
+		t.CancelEdit()
+		return true
+	case key.Key == gui.KeyF2 && !t.IsEditing() && t.list.Count() > 0:
+		for column := range t.columns {
+			if t.EditCell(t.sourceRow(t.list.CurrentIndex()), column) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// tableLayout puts the header above the rows and the editor over its cell
+type tableLayout struct {
+	table *Table
+}
+
+// Measure asks for the rows' size plus the header
+func (l tableLayout) Measure(children []gui.GUIElement, constraints gui.Constraints) gui.Size {
+	t := l.table
+	size := gui.MeasureElement(t.list, constraints.Deflate(gui.Insets{Top: t.headerHeight}))
+	size.Height += t.headerHeight
+	return size
+}
+
+// Arrange gives the header its row and the rows the rest
+func (l tableLayout) Arrange(children []gui.GUIElement, area image.Rectangle) {
+	t := l.table
+	split := minInt(area.Min.Y+t.headerHeight, area.Max.Y)
+	gui.ArrangeElement(t.header, image.Rect(area.Min.X, area.Min.Y, area.Max.X, split))
+	gui.ArrangeElement(t.list, image.Rect(area.Min.X, split, area.Max.X, area.Max.Y))
+	t.placeEditor()
+}
+
+// tableItems presents a table's rows to its list in display order
+type tableItems struct {
+	table *Table
+}
+
+// Count returns the number of rows
+func (s tableItems) Count() int {
+	if s.table.source == nil {
+		return 0
+	}
+	return s.table.source.RowCount()
+}
+
+// Item returns the source row shown at index
+func (s tableItems) Item(index int) interface{} {
+	return s.table.sourceRow(index)
+}
+
+// tableRowDelegate creates the rows a table's list shows
+type tableRowDelegate struct {
+	table *Table
+}
+
+// CreateRow returns an unbound table row
+func (d tableRowDelegate) CreateRow() gui.GUIElement {
+	return &tableRow{Element: gui.NewElement(0, 0, 0, 0), table: d.table}
+}
+
+// BindRow points a table row at a source row
+func (d tableRowDelegate) BindRow(row gui.GUIElement, index int, item interface{}, selected bool) {
+	r := row.(*tableRow)
+	r.row, r.selected = item.(int), selected
+	_, _, width, height := r.GetBounds()
+	r.InvalidateRect(0, 0, width, height)
+}
+
+// tableRow draws the cells of one source row
+type tableRow struct {
+	*gui.Element
+	table    *Table
+	row      int
+	selected bool
+}
+
+// Render draws each cell with its column's renderer, then the grid lines
+func (r *tableRow) Render(canvas gui.Canvas) error {
+	if !r.IsVisible() {
+		return nil
+	}
+	t := r.table
+	x, y, _, height := r.GetBounds()
+	left := x
+	for i, column := range t.columns {
+		renderer := column.Renderer
+		if renderer == nil {
+			renderer = TextCellRenderer{}
+		}
+		bounds := image.Rect(left, y, left+column.Width, y+height)
+		if err := renderer.RenderCell(canvas, bounds, t.source.Value(r.row, i), column, r.selected); err != nil {
+			return err
+		}
+		left += column.Width
+		if err := canvas.DrawRectangle(left-1, y, 1, height, t.gridColor, true); err != nil {
+			return err
+		}
+	}
+	return canvas.DrawRectangle(x, y+height-1, left-x, 1, t.gridColor, true)
+}
+
+// tableHeader is the row of column titles, scrolled sideways with the rows
+type tableHeader struct {
+	*gui.Element
+	table *Table
+}
+
+// Render draws the titles, the sort direction and the dividers
+func (h *tableHeader) Render(canvas gui.Canvas) error {
+	if !h.IsVisible() {
+		return nil
+	}
+	x, y, width, height := h.GetBounds()
+	canvas.Push()
+	canvas.SetClippingRegion(x, y, width, height)
+	err := h.renderColumns(canvas, x, y, width, height)
+	canvas.Pop()
+	return err
+}
+
+// renderColumns draws the header within its clipping region
+func (h *tableHeader) renderColumns(canvas gui.Canvas, x, y, width, height int) error {
+	t := h.table
+	if err := canvas.DrawRectangle(x, y, width, height, t.headerColor, true); err != nil {
+		return err
+	}
+
+	scrollX, _ := t.list.ScrollPosition()
+	left := x - scrollX
+	for i, column := range t.columns {
+		title := column.Header
+		if i == t.sortColumn && t.ascending {
+			title += " ^"
+		} else if i == t.sortColumn {
+			title += " v"
+		}
+		bounds := image.Rect(left, y, left+column.Width, y+height)
+		if err := drawCellText(canvas, bounds, title, column.Alignment, t.headerFont, colorful.Color{}); err != nil {
+			return err
+		}
+		left += column.Width
+		if err := canvas.DrawRectangle(left-1, y, 1, height, t.gridColor, true); err != nil {
+			return err
+		}
+	}
+	if err := canvas.DrawRectangle(x, y+height-1, width, 1, t.gridColor, true); err != nil {
+		return err
+	}
+	return h.Element.Render(canvas)
+}
+
+// headerLayout places the column grips over the dividers
+type headerLayout struct {
+	table *Table
+}
+
+// Measure asks for the width of the columns and the header's height
+func (l headerLayout) Measure(children []gui.GUIElement, constraints gui.Constraints) gui.Size {
+	return gui.Size{Width: l.table.totalWidth(), Height: l.table.headerHeight}
+}
+
+// Arrange centres a grip on the right edge of each column
+func (l headerLayout) Arrange(children []gui.GUIElement, area image.Rectangle) {
+	t := l.table
+	scrollX, _ := t.list.ScrollPosition()
+	left := area.Min.X - scrollX
+	for i, grip := range t.grips {
+		left += t.columns[i].Width
+		gui.ArrangeElement(grip, image.Rect(left-gripWidth/2, area.Min.Y, left+gripWidth/2, area.Max.Y))
+	}
+}
+
+// columnGrip resizes the column to its left when dragged
+type columnGrip struct {
+	*gui.Element
+	table      *Table
+	column     int
+	startWidth int
+}
+
+// newColumnGrip creates the grip for a column's right divider
+func newColumnGrip(t *Table, column int) *columnGrip {
+	g := &columnGrip{Element: gui.NewElement(0, 0, 0, 0), table: t, column: column}
+	g.SetCursor(gui.CursorResizeEW)
+	g.AddEventHandler(gui.EventTypeMouseDown, gui.EventHandlerFunc(g.handleMouseDown))
+	g.AddEventHandler(gui.EventTypeMouseDrag, gui.EventHandlerFunc(g.handleMouseDrag))
+	g.AddEventHandler(gui.EventTypeClick, gui.EventHandlerFunc(g.handleClick))
+	return g
+}
+
+// handleMouseDown remembers the width the drag starts from
+func (g *columnGrip) handleMouseDown(event gui.Event) bool {
+	if event.(*gui.MouseDownEvent).Button != gui.MouseButtonLeft {
+		return false
+	}
+	g.startWidth = g.table.ColumnWidth(g.column)
+	return true
+}
+
+// handleMouseDrag resizes the column with the pointer
+func (g *columnGrip) handleMouseDrag(event gui.Event) bool {
+	drag := event.(*gui.MouseDragEvent)
+	g.table.SetColumnWidth(g.column, g.startWidth+drag.X-drag.StartX)
+	return true
+}
+
+// handleClick keeps a press on the divider from sorting by the column
+func (g *columnGrip) handleClick(event gui.Event) bool {
+	return true
+}
+
+// drawCellText draws a line of text within a cell, inset by CellPadding,
+// centred vertically and shortened to fit
+func drawCellText(canvas gui.Canvas, bounds image.Rectangle, text string, alignment TextAlignment, face font.Face, color colorful.Color) error {
+	if face == nil {
+		face = basicfont.Face7x13
+	}
+	width := bounds.Dx() - 2*CellPadding
+	if text == "" || width <= 0 {
+		return nil
+	}
+	text = ellipsize(text, face, width)
+	metrics := face.Metrics()
+	lineHeight := (metrics.Ascent + metrics.Descent).Ceil()
+	x := alignTextX(alignment, bounds.Min.X+CellPadding, width, measureTextWidth(text, face))
+	y := bounds.Min.Y + (bounds.Dy()-lineHeight)/2 + metrics.Ascent.Ceil()
+	return canvas.DrawText(text, x, y, face, color)
+}
+
+// cellText formats a value for display and editing, showing nil as empty
+func cellText(value interface{}) string {
+	if value == nil {
+		return ""
+	}
+	return fmt.Sprint(value)
+}
+
+// compareValues orders two cell values: numbers numerically, strings
+// ignoring case, times chronologically, nil first and anything else by
+// its text
+func compareValues(a, b interface{}) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return -1
+	case b == nil:
+		return 1
+	}
+
+	if x, ok := toFloat(a); ok {
+		if y, ok := toFloat(b); ok {
+			switch {
+			case x < y:
+				return -1
+			case x > y:
+				return 1
+			}
+			return 0
+		}
+	}
+
+	switch x := a.(type) {
+	case time.Time:
+		if y, ok := b.(time.Time); ok {
+			switch {
+			case x.Before(y):
+				return -1
+			case x.After(y):
+				return 1
+			}
+			return 0
+		}
+	case bool:
+		if y, ok := b.(bool); ok {
+			switch {
+			case x == y:
+				return 0
+			case y:
+				return -1
+			}
+			return 1
+		}
+	}
+
+	sa, sb := fmt.Sprint(a), fmt.Sprint(b)
+	if c := strings.Compare(strings.ToLower(sa), strings.ToLower(sb)); c != 0 {
+		return c
+	}
+	return strings.Compare(sa, sb)
+}
+
+// toFloat converts any number to a float64
+func toFloat(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), true
+	case int8:
+		return float64(n), true
+	case int16:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case uint:
+		return float64(n), true
+	case uint8:
+		return float64(n), true
+	case uint16:
+		return float64(n), true
+	case uint32:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	case float32:
+		return float64(n), true
+	case float64:
+		return n, true
+	}
+	return 0, false
+}
+
+// parseLike parses text as the type of an existing value, for the common
+// number and bool types, and keeps it as a string otherwise
+func parseLike(old interface{}, text string) (interface{}, error) {
+	trimmed := strings.TrimSpace(text)
+	switch old.(type) {
+	case int:
+		return strconv.Atoi(trimmed)
+	case int64:
+		return strconv.ParseInt(trimmed, 10, 64)
+	case float64:
+		return strconv.ParseFloat(trimmed, 64)
+	case bool:
+		return strconv.ParseBool(trimmed)
+	}
+	return text, nil
+}
@@ -0,0 +1,63 @@
+package gui
+
+import "errors"
+
+// ErrWindowClosed is returned when work cannot run because the window closed
+var ErrWindowClosed = errors.New("window closed")
+
+// Post queues fn to run on the UI loop and returns immediately.
+// It is safe to call from any goroutine; component state such as a
+// Label's text must only be changed from the UI loop, so background
+// goroutines use Post to update the interface.
+func (w *Window) Post(fn func()) {
+	w.postMu.Lock()
+	w.posted = append(w.posted, fn)
+	w.postMu.Unlock()
+
+	w.renderer.Wake()
+}
+
+// Invoke runs fn on the UI loop and waits for it to finish. It returns
+// ErrWindowClosed if the window is not running or closes before fn runs.
+// Invoke must not be called from the UI loop itself, which would deadlock.
+func (w *Window) Invoke(fn func()) error {
+	w.mu.RLock()
+	running, closed := w.running, w.closed
+	w.mu.RUnlock()
+
+	if !running {
+		return ErrWindowClosed
+	}
+
+	done := make(chan struct{})
+	w.Post(func() {
+		defer close(done)
+		fn()
+	})
+
+	select {
+	case <-done:
+		return nil
+	case <-closed:
+		return ErrWindowClosed
+	}
+}
+
+// hasPosted reports whether queued closures are waiting to run
+func (w *Window) hasPosted() bool {
+	w.postMu.Lock()
+	defer w.postMu.Unlock()
+	return len(w.posted) > 0
+}
+
+// runPosted drains the queue of posted closures on the UI loop
+func (w *Window) runPosted() {
+	w.postMu.Lock()
+	posted := w.posted
+	w.posted = nil
+	w.postMu.Unlock()
+
+	for _, fn := range posted {
+		fn()
+	}
+}
@@ -0,0 +1,226 @@
+package gui
+
+import (
+	"context"
+	"errors"
+	"image"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/lucasb-eyer/go-colorful"
+	"golang.org/x/image/font"
+)
+
+// testRenderer is a headless Renderer whose WaitEvents only returns on a
+// Wake or a timeout
+type testRenderer struct {
+	width, height int
+	wake          chan struct{}
+}
+
+func newTestRenderer(width, height int) *testRenderer {
+	return &testRenderer{width: width, height: height, wake: make(chan struct{}, 1)}
+}
+
+func (r *testRenderer) Show(title string) error                     { return nil }
+func (r *testRenderer) Close() error                                { return nil }
+func (r *testRenderer) CreateCanvas() (Canvas, error)               { return testCanvas{}, nil }
+func (r *testRenderer) PollEvents() []Event                         { return nil }
+func (r *testRenderer) SetCursor(shape CursorShape) error           { return nil }
+func (r *testRenderer) SetTextInputRect(rect image.Rectangle) error { return nil }
+func (r *testRenderer) Clipboard() Clipboard                        { return nil }
+func (r *testRenderer) Size() (width, height int)                   { return r.width, r.height }
+func (r *testRenderer) SetSize(width, height int) error             { return nil }
+
+func (r *testRenderer) WaitEvents(timeout time.Duration) []Event {
+	if timeout < 0 {
+		<-r.wake
+		return nil
+	}
+	select {
+	case <-r.wake:
+	case <-time.After(timeout):
+	}
+	return nil
+}
+
+func (r *testRenderer) Wake() {
+	select {
+	case r.wake <- struct{}{}:
+	default:
+	}
+}
+
+// testCanvas discards all drawing
+type testCanvas struct{}
+
+func (testCanvas) DrawText(text string, x, y int, font font.Face, color colorful.Color) error {
+	return nil
+}
+func (testCanvas) DrawRectangle(x, y, width, height int, color colorful.Color, filled bool) error {
+	return nil
+}
+func (testCanvas) DrawCircle(x, y, radius int, color colorful.Color, filled bool) error {
+	return nil
+}
+func (testCanvas) DrawImage(img image.Image, x, y, width, height int) error { return nil }
+func (testCanvas) SetClippingRegion(x, y, width, height int)                {}
+func (testCanvas) ClearClippingRegion()                                     {}
+func (testCanvas) Push()                                                    {}
+func (testCanvas) Pop()                                                     {}
+func (testCanvas) Translate(dx, dy float64)                                 {}
+func (testCanvas) Scale(sx, sy float64)                                     {}
+func (testCanvas) Rotate(radians float64)                                   {}
+func (testCanvas) Clear(color colorful.Color) error                         { return nil }
+func (testCanvas) Present(damage image.Rectangle) error                     { return nil }
+
+// runTestWindow starts a headless window's loop and stops it when the test ends
+func runTestWindow(t *testing.T) *Window {
+	t.Helper()
+	w, err := newWindow("test", newTestRenderer(200, 100), 200, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Show(); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- w.Run(ctx) }()
+	t.Cleanup(func() {
+		cancel()
+		if err := <-done; err != nil && !errors.Is(err, context.Canceled) {
+			t.Errorf("Run: %v", err)
+		}
+	})
+	return w
+}
+
+func TestPostRunsInOrder(t *testing.T) {
+	w := runTestWindow(t)
+
+	var got []int // Only touched on the loop
+	for i := 0; i < 1000; i++ {
+		i := i
+		w.Post(func() { got = append(got, i) })
+	}
+	var result []int
+	if err := w.Invoke(func() { result = append(result, got...) }); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(result) != 1000 {
+		t.Fatalf("ran %d closures, want 1000", len(result))
+	}
+	for i, v := range result {
+		if v != i {
+			t.Fatalf("closure %d ran at position %d", v, i)
+		}
+	}
+}
+
+func TestPostAndInvokeFromManyGoroutines(t *testing.T) {
+	w := runTestWindow(t)
+
+	const goroutines, calls = 32, 200
+	count := 0                      // Only touched on the loop; the race detector checks this
+	last := make([]int, goroutines) // Last call seen per goroutine, to check FIFO order
+	for g := range last {
+		last[g] = -1
+	}
+	disordered := 0
+
+	var wg sync.WaitGroup
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < calls; i++ {
+				i := i
+				fn := func() {
+					count++
+					if last[g] != i-1 {
+						disordered++
+					}
+					last[g] = i
+				}
+				if i%10 == 0 {
+					if err := w.Invoke(fn); err != nil {
+						t.Error(err)
+						return
+					}
+				} else {
+					w.Post(fn)
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	var total, disorders int
+	if err := w.Invoke(func() { total, disorders = count, disordered }); err != nil {
+		t.Fatal(err)
+	}
+	if total != goroutines*calls {
+		t.Errorf("ran %d closures, want %d", total, goroutines*calls)
+	}
+	if disorders != 0 {
+		t.Errorf("%d closures ran out of their goroutine's posting order", disorders)
+	}
+}
+
+func TestInvokeAfterClose(t *testing.T) {
+	w, err := newWindow("test", newTestRenderer(200, 100), 200, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := w.Invoke(func() {}); !errors.Is(err, ErrWindowClosed) {
+		t.Errorf("Invoke before Show = %v, want ErrWindowClosed", err)
+	}
+
+	if err := w.Show(); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	ran := false
+	if err := w.Invoke(func() { ran = true }); !errors.Is(err, ErrWindowClosed) {
+		t.Errorf("Invoke after Close = %v, want ErrWindowClosed", err)
+	}
+	if ran {
+		t.Error("Invoke ran its function after Close")
+	}
+}
+
+func TestInvokePendingAtClose(t *testing.T) {
+	w, err := newWindow("test", newTestRenderer(200, 100), 200, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Show(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Nothing drains the queue, so Invoke waits until the window closes
+	result := make(chan error, 1)
+	go func() { result <- w.Invoke(func() {}) }()
+	for !w.hasPosted() {
+		time.Sleep(time.Millisecond)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case err := <-result:
+		if !errors.Is(err, ErrWindowClosed) {
+			t.Errorf("pending Invoke = %v, want ErrWindowClosed", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("pending Invoke did not return after Close")
+	}
+}
@@ -0,0 +1,224 @@
+package gui
+
+import "image"
+
+// DragThreshold is how far, in pixels, the pointer must move with a button
+// held before a press on a drag source turns into a drag
+const DragThreshold = 4
+
+// DragTypeFiles is the type of data dropped from other applications by
+// backends that support file drops; its value is a []string of paths
+const DragTypeFiles = "application/x-file-list"
+
+// DropEffect says what a drop does with the dragged data
+type DropEffect int
+
+const (
+	DropNone DropEffect = iota
+	DropCopy
+	DropMove
+	DropLink
+)
+
+// DragData is the payload carried by a drag
+type DragData struct {
+	Type    string      // Application defined kind of payload, such as "kanban/card"
+	Value   interface{} // The payload itself, asserted by drop targets according to Type
+	Preview image.Image // Drawn under the pointer while dragging, may be nil
+	HotSpot image.Point // Point of the preview that stays under the pointer
+
+	// OnEnd is called once the drag finishes, with DropNone when it was
+	// cancelled or dropped where nothing accepted it
+	OnEnd func(effect DropEffect)
+}
+
+// DragSourceFunc starts a drag from an element pressed at x, y, returning
+// nil to refuse
+type DragSourceFunc func(x, y int) *DragData
+
+// SetDragSource makes the element draggable; nil turns dragging off
+func (e *Element) SetDragSource(source DragSourceFunc) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.dragSource = source
+}
+
+// DragSource returns the function starting drags from the element, or nil
+func (e *Element) DragSource() DragSourceFunc {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.dragSource
+}
+
+// Overlay returns the window's overlay layer, whose children are drawn
+// above all other elements and never receive pointer events
+func (w *Window) Overlay() *Element {
+	return w.overlay
+}
+
+// IsDragging reports whether a drag and drop is in progress
+func (w *Window) IsDragging() bool {
+	return w.drag != nil
+}
+
+// dragSession tracks a drag in progress; it is only used on the UI loop
+type dragSession struct {
+	data    *DragData
+	over    []GUIElement // Elements under the pointer, outermost first
+	effect  DropEffect   // Effect accepted by the last DragOverEvent
+	preview *dragPreview
+}
+
+// dragPreview draws a drag's preview image on the overlay layer
+type dragPreview struct {
+	*Element
+	img image.Image
+}
+
+// Render draws the preview image at the element's bounds
+func (p *dragPreview) Render(canvas Canvas) error {
+	x, y, width, height := p.GetBounds()
+	return canvas.DrawImage(p.img, x, y, width, height)
+}
+
+// maybeStartDrag starts a drag once the pointer moved far enough from a
+// press on a drag source, reporting whether it did
+func (w *Window) maybeStartDrag(x, y int) bool {
+	p := &w.pointer
+	if p.target == nil || p.captured != nil || p.consumed || p.button != MouseButtonLeft {
+		return false
+	}
+	dx, dy := x-p.startX, y-p.startY
+	if dx*dx+dy*dy < DragThreshold*DragThreshold {
+		return false
+	}
+
+	// The innermost drag source on the way to the press target wins
+	path, ok := w.Element.pathTo(p.target)
+	if !ok {
+		return false
+	}
+	for i := len(path) - 1; i >= 0; i-- {
+		base := baseOf(path[i])
+		if base == nil {
+			continue
+		}
+		source := base.DragSource()
+		if source == nil {
+			continue
+		}
+		data := source(p.startX, p.startY)
+		if data == nil {
+			return false
+		}
+		w.startDrag(data, x, y)
+		return true
+	}
+	return false
+}
+
+// startDrag begins a drag session, showing the preview on the overlay
+func (w *Window) startDrag(data *DragData, x, y int) {
+	w.drag = &dragSession{data: data}
+
+	// Hover is frozen while dragging, so clear it like a pointer leaving
+	w.updateHover(nil, x, y)
+
+	if data.Preview != nil {
+		size := data.Preview.Bounds().Size()
+		preview := &dragPreview{
+			Element: NewElement(x-data.HotSpot.X, y-data.HotSpot.Y, size.X, size.Y),
+			img:     data.Preview,
+		}
+		w.drag.preview = preview
+		w.overlay.AddChild(preview)
+	}
+
+	w.dragMove(x, y)
+}
+
+// dragMove moves the preview, sends enter and leave events as the drag
+// crosses elements and asks the element under the pointer to accept it
+func (w *Window) dragMove(x, y int) {
+	d := w.drag
+	if d.preview != nil {
+		d.preview.SetPosition(x-d.data.HotSpot.X, y-d.data.HotSpot.Y)
+	}
+
+	path := w.hoverPathAt(x, y)
+	common := 0
+	for common < len(path) && common < len(d.over) && path[common] == d.over[common] {
+		common++
+	}
+	left := d.over[common:]
+	d.over = path
+
+	for i := len(left) - 1; i >= 0; i-- {
+		dispatchTo(left[i], NewDragLeaveEvent(x, y, d.data))
+	}
+	for _, el := range path[common:] {
+		dispatchTo(el, NewDragEnterEvent(x, y, d.data))
+	}
+
+	over := NewDragOverEvent(x, y, d.data)
+	if w.ContainsPoint(x, y) {
+		dispatchAlong(append([]GUIElement{w}, path...), over)
+	}
+	d.effect = over.Effect()
+	w.showDropEffect(d.effect)
+}
+
+// showDropEffect sets the cursor to reflect whether a drop would be accepted
+func (w *Window) showDropEffect(effect DropEffect) {
+	shape := CursorNotAllowed
+	switch effect {
+	case DropMove:
+		shape = CursorMove
+	case DropCopy, DropLink:
+		shape = CursorHand
+	}
+	if shape != w.cursor && w.renderer.SetCursor(shape) == nil {
+		w.cursor = shape
+	}
+}
+
+// finishDrop drops the data on the element under the pointer if it
+// accepted the drag, then ends the session
+func (w *Window) finishDrop(x, y int) {
+	d := w.drag
+	w.dragMove(x, y)
+
+	effect := d.effect
+	if effect != DropNone {
+		drop := NewDropEvent(x, y, d.data, effect)
+		dispatchAlong(append([]GUIElement{w}, d.over...), drop)
+		effect = drop.Effect()
+	}
+	w.endDrag(x, y, effect)
+}
+
+// cancelDrag abandons the drag without dropping; the button is still held,
+// so the press is marked used to keep the drag from starting again
+func (w *Window) cancelDrag(x, y int) {
+	w.pointer.consumed = w.pointer.pressed
+	w.endDrag(x, y, DropNone)
+}
+
+// endDrag tells the elements under the pointer and the source that the
+// drag is over and restores hover tracking
+func (w *Window) endDrag(x, y int, effect DropEffect) {
+	d := w.drag
+	w.drag = nil
+
+	for i := len(d.over) - 1; i >= 0; i-- {
+		dispatchTo(d.over[i], NewDragLeaveEvent(x, y, d.data))
+	}
+	if d.preview != nil {
+		w.overlay.RemoveChild(d.preview)
+	}
+	if d.data.OnEnd != nil {
+		d.data.OnEnd(effect)
+	}
+
+	w.updateHover(w.hoverPathAt(x, y), x, y)
+}
@@ -13,14 +13,57 @@ const (
 	EventTypeBlur
 	EventTypeMouseMove
 	EventTypeResize
+	EventTypeTimer
+	EventTypeMouseDown
+	EventTypeMouseUp
+	EventTypeMouseDrag
+	EventTypeWheel
+	EventTypeDoubleClick
+	EventTypeKeyRelease
+	EventTypeMouseEnter
+	EventTypeMouseLeave
+	EventTypeDragEnter
+	EventTypeDragOver
+	EventTypeDragLeave
+	EventTypeDrop
+	EventTypeCompositionStart
+	EventTypeCompositionUpdate
+	EventTypeCompositionEnd
+	EventTypeTouchStart
+	EventTypeTouchMove
+	EventTypeTouchEnd
+	EventTypeTouchCancel
 )
 
 // Event defines the interface for all GUI events
 type Event interface {
 	Type() EventType
 	Timestamp() time.Time
+
+	// Propagation state, provided by embedding BaseEvent
+	Target() GUIElement
+	CurrentTarget() GUIElement
+	Phase() EventPhase
+	StopPropagation()
+	IsPropagationStopped() bool
+	PreventDefault()
+	IsDefaultPrevented() bool
 }
 
+// EventPhase tells which stage of dispatch an event is in
+type EventPhase int
+
+const (
+	// PhaseNone is used for events that are broadcast rather than targeted
+	PhaseNone EventPhase = iota
+	// PhaseCapturing runs capture handlers from the root down to the target's parent
+	PhaseCapturing
+	// PhaseAtTarget runs the target's own handlers
+	PhaseAtTarget
+	// PhaseBubbling runs handlers from the target's parent back up to the root
+	PhaseBubbling
+)
+
 // EventHandler processes GUI events
 type EventHandler interface {
 	Handle(event Event) bool
@@ -35,8 +78,13 @@ func (f EventHandlerFunc) Handle(event Event) bool {
 
 // BaseEvent provides common event functionality
 type BaseEvent struct {
-	eventType EventType
-	timestamp time.Time
+	eventType        EventType
+	timestamp        time.Time
+	target           GUIElement
+	currentTarget    GUIElement
+	phase            EventPhase
+	stopped          bool
+	defaultPrevented bool
 }
 
 func NewBaseEvent(eventType EventType) BaseEvent {
@@ -54,11 +102,86 @@ func (e BaseEvent) Timestamp() time.Time {
 	return e.timestamp
 }
 
-// ClickEvent represents mouse click events
+// Target returns the element the event was dispatched to, or nil for broadcast events
+func (e *BaseEvent) Target() GUIElement {
+	return e.target
+}
+
+// CurrentTarget returns the element whose handlers are running
+func (e *BaseEvent) CurrentTarget() GUIElement {
+	return e.currentTarget
+}
+
+// Phase returns the current dispatch phase
+func (e *BaseEvent) Phase() EventPhase {
+	return e.phase
+}
+
+// StopPropagation keeps the event from reaching further elements; the
+// remaining handlers of the current element still run
+func (e *BaseEvent) StopPropagation() {
+	e.stopped = true
+}
+
+// IsPropagationStopped reports whether StopPropagation was called
+func (e *BaseEvent) IsPropagationStopped() bool {
+	return e.stopped
+}
+
+// PreventDefault asks components to skip their built-in reaction, such as
+// an Input inserting typed text or a click being synthesized from a release
+func (e *BaseEvent) PreventDefault() {
+	e.defaultPrevented = true
+}
+
+// IsDefaultPrevented reports whether PreventDefault was called
+func (e *BaseEvent) IsDefaultPrevented() bool {
+	return e.defaultPrevented
+}
+
+// baseEvent exposes the propagation state for dispatch
+func (e *BaseEvent) baseEvent() *BaseEvent {
+	return e
+}
+
+// PositionedEvent is implemented by events that occur at a pointer position
+type PositionedEvent interface {
+	Event
+	Position() (x, y int)
+}
+
+// PointerEvent holds the coordinates shared by pointer events
+type PointerEvent struct {
+	X, Y           int // Window coordinates
+	LocalX, LocalY int // Coordinates relative to the current target
+}
+
+// newPointerEvent creates pointer coordinates local to the window
+func newPointerEvent(x, y int) PointerEvent {
+	return PointerEvent{X: x, Y: y, LocalX: x, LocalY: y}
+}
+
+// Position returns the pointer position in window coordinates
+func (p *PointerEvent) Position() (x, y int) {
+	return p.X, p.Y
+}
+
+// Local returns the pointer position relative to the current target's origin
+func (p *PointerEvent) Local() (x, y int) {
+	return p.LocalX, p.LocalY
+}
+
+// setLocal updates the local coordinates as dispatch moves between elements
+func (p *PointerEvent) setLocal(x, y int) {
+	p.LocalX, p.LocalY = x, y
+}
+
+// ClickEvent represents a press and release of a mouse button on the same element
 type ClickEvent struct {
 	BaseEvent
-	X, Y   int
-	Button MouseButton
+	PointerEvent
+	Button    MouseButton
+	Modifiers KeyModifiers
 }
 
 type MouseButton int
@@ -71,82 +194,122 @@ const (
 
 func NewClickEvent(x, y int, button MouseButton) *ClickEvent {
 	return &ClickEvent{
-		BaseEvent: NewBaseEvent(EventTypeClick),
-		X:         x,
-		Y:         y,
-		Button:    button,
+		BaseEvent:    NewBaseEvent(EventTypeClick),
+		PointerEvent: newPointerEvent(x, y),
+		Button:       button,
 	}
 }
 
-// KeyPressEvent represents keyboard input
-type KeyPressEvent struct {
+// MouseDownEvent represents a mouse button being pressed
+type MouseDownEvent struct {
 	BaseEvent
-	Key       Key
+	PointerEvent
+	Button    MouseButton
 	Modifiers KeyModifiers
 }
 
-type Key int
+func NewMouseDownEvent(x, y int, button MouseButton) *MouseDownEvent {
+	return &MouseDownEvent{
+		BaseEvent:    NewBaseEvent(EventTypeMouseDown),
+		PointerEvent: newPointerEvent(x, y),
+		Button:       button,
+	}
+}
 
-const (
-	KeyUnknown Key = iota
-	KeyA
-	KeyB
-	KeyC
-	KeyD
-	KeyE
-	KeyF
-	KeyG
-	KeyH
-	KeyI
-	KeyJ
-	KeyK
-	KeyL
-	KeyM
-	KeyN
-	KeyO
-	KeyP
-	KeyQ
-	KeyR
-	KeyS
-	KeyT
-	KeyU
-	KeyV
-	KeyW
-	KeyX
-	KeyY
-	KeyZ
-	Key0
-	Key1
-	Key2
-	Key3
-	Key4
-	Key5
-	Key6
-	Key7
-	Key8
-	Key9
-	KeySpace
-	KeyEnter
-	KeyTab
-	KeyBackspace
-	KeyDelete
-	KeyEscape
-	KeyArrowUp
-	KeyArrowDown
-	KeyArrowLeft
-	KeyArrowRight
-)
+// MouseUpEvent represents a mouse button being released; it is delivered
+// to the element that received the matching MouseDownEvent
+type MouseUpEvent struct {
+	BaseEvent
+	PointerEvent
+	Button    MouseButton
+	Modifiers KeyModifiers
+}
+
+func NewMouseUpEvent(x, y int, button MouseButton) *MouseUpEvent {
+	return &MouseUpEvent{
+		BaseEvent:    NewBaseEvent(EventTypeMouseUp),
+		PointerEvent: newPointerEvent(x, y),
+		Button:       button,
+	}
+}
 
-type KeyModifiers int
+// MouseDragEvent represents pointer movement while a button is held; it is
+// delivered to the element that received the MouseDownEvent
+type MouseDragEvent struct {
+	BaseEvent
+	PointerEvent
+	Button         MouseButton
+	StartX, StartY int // Where the button was pressed
+	DeltaX, DeltaY int // Movement since the previous drag event
+}
+
+func NewMouseDragEvent(x, y int, button MouseButton, startX, startY, deltaX, deltaY int) *MouseDragEvent {
+	return &MouseDragEvent{
+		BaseEvent:    NewBaseEvent(EventTypeMouseDrag),
+		PointerEvent: newPointerEvent(x, y),
+		Button:       button,
+		StartX:       startX,
+		StartY:       startY,
+		DeltaX:       deltaX,
+		DeltaY:       deltaY,
+	}
+}
+
+// WheelUnit describes how wheel deltas are measured
+type WheelUnit int
 
 const (
-	ModifierNone  KeyModifiers = 0
-	ModifierShift KeyModifiers = 1 << iota
-	ModifierCtrl
-	ModifierAlt
-	ModifierSuper
+	// WheelUnitLine deltas count notches of a classic scroll wheel
+	WheelUnitLine WheelUnit = iota
+	// WheelUnitPixel deltas are precise pixel distances from touchpads
+	WheelUnitPixel
 )
 
+// WheelEvent represents scrolling with a mouse wheel or touchpad;
+// positive deltas scroll right and down
+type WheelEvent struct {
+	BaseEvent
+	PointerEvent
+	DeltaX, DeltaY float64
+	Unit           WheelUnit
+	Modifiers      KeyModifiers
+}
+
+func NewWheelEvent(x, y int, deltaX, deltaY float64, unit WheelUnit) *WheelEvent {
+	return &WheelEvent{
+		BaseEvent:    NewBaseEvent(EventTypeWheel),
+		PointerEvent: newPointerEvent(x, y),
+		DeltaX:       deltaX,
+		DeltaY:       deltaY,
+		Unit:         unit,
+	}
+}
+
+// DoubleClickEvent represents a second click on the same element in quick succession
+type DoubleClickEvent struct {
+	BaseEvent
+	PointerEvent
+	Button    MouseButton
+	Modifiers KeyModifiers
+}
+
+func NewDoubleClickEvent(x, y int, button MouseButton) *DoubleClickEvent {
+	return &DoubleClickEvent{
+		BaseEvent:    NewBaseEvent(EventTypeDoubleClick),
+		PointerEvent: newPointerEvent(x, y),
+		Button:       button,
+	}
+}
+
+// KeyPressEvent represents keyboard input
+type KeyPressEvent struct {
+	BaseEvent
+	Key       Key          // Logical key after applying the keyboard layout
+	Scancode  Scancode     // Physical key position, independent of layout
+	Modifiers KeyModifiers // Modifier state when the key went down
+	Repeat    bool         // Generated by auto-repeat while the key is held
+}
+
 func NewKeyPressEvent(key Key, modifiers KeyModifiers) *KeyPressEvent {
 	return &KeyPressEvent{
 		BaseEvent: NewBaseEvent(EventTypeKeyPress),
@@ -155,6 +318,22 @@ func NewKeyPressEvent(key Key, modifiers KeyModifiers) *KeyPressEvent {
 	}
 }
 
+// KeyReleaseEvent represents a key being released
+type KeyReleaseEvent struct {
+	BaseEvent
+	Key       Key
+	Scancode  Scancode
+	Modifiers KeyModifiers
+}
+
+func NewKeyReleaseEvent(key Key, modifiers KeyModifiers) *KeyReleaseEvent {
+	return &KeyReleaseEvent{
+		BaseEvent: NewBaseEvent(EventTypeKeyRelease),
+		Key:       key,
+		Modifiers: modifiers,
+	}
+}
+
 // TextInputEvent represents text input
 type TextInputEvent struct {
 	BaseEvent
@@ -193,17 +372,246 @@ func NewBlurEvent() *BlurEvent {
 // MouseMoveEvent represents mouse movement
 type MouseMoveEvent struct {
 	BaseEvent
-	X, Y int
+	PointerEvent
 }
 
 func NewMouseMoveEvent(x, y int) *MouseMoveEvent {
 	return &MouseMoveEvent{
-		BaseEvent: NewBaseEvent(EventTypeMouseMove),
-		X:         x,
-		Y:         y,
+		BaseEvent:    NewBaseEvent(EventTypeMouseMove),
+		PointerEvent: newPointerEvent(x, y),
+	}
+}
+
+// MouseEnterEvent is sent to an element when the pointer moves onto it or
+// one of its descendants; it does not bubble
+type MouseEnterEvent struct {
+	BaseEvent
+	PointerEvent
+}
+
+func NewMouseEnterEvent(x, y int) *MouseEnterEvent {
+	return &MouseEnterEvent{
+		BaseEvent:    NewBaseEvent(EventTypeMouseEnter),
+		PointerEvent: newPointerEvent(x, y),
+	}
+}
+
+// MouseLeaveEvent is sent to an element when the pointer moves off it and
+// all of its descendants; renderers send it to the window when the pointer
+// leaves the window
+type MouseLeaveEvent struct {
+	BaseEvent
+	PointerEvent
+}
+
+func NewMouseLeaveEvent(x, y int) *MouseLeaveEvent {
+	return &MouseLeaveEvent{
+		BaseEvent:    NewBaseEvent(EventTypeMouseLeave),
+		PointerEvent: newPointerEvent(x, y),
+	}
+}
+
+// DragEvent holds what drag and drop events share: the pointer position,
+// the dragged data and the drop effect a target accepted
+type DragEvent struct {
+	BaseEvent
+	PointerEvent
+	Data   *DragData
+	effect DropEffect
+}
+
+// Accept tells the window the element under the pointer would take the
+// data with the given effect
+func (e *DragEvent) Accept(effect DropEffect) {
+	e.effect = effect
+}
+
+// Reject tells the window the data cannot be dropped here
+func (e *DragEvent) Reject() {
+	e.effect = DropNone
+}
+
+// Effect returns the accepted drop effect, DropNone unless a handler accepted
+func (e *DragEvent) Effect() DropEffect {
+	return e.effect
+}
+
+func newDragEvent(eventType EventType, x, y int, data *DragData) DragEvent {
+	return DragEvent{
+		BaseEvent:    NewBaseEvent(eventType),
+		PointerEvent: newPointerEvent(x, y),
+		Data:         data,
 	}
 }
 
+// DragEnterEvent is sent to an element when a drag moves onto it or one
+// of its descendants; it does not bubble
+type DragEnterEvent struct {
+	DragEvent
+}
+
+func NewDragEnterEvent(x, y int, data *DragData) *DragEnterEvent {
+	return &DragEnterEvent{DragEvent: newDragEvent(EventTypeDragEnter, x, y, data)}
+}
+
+// DragOverEvent is dispatched to the element under the pointer on every
+// move of a drag; a handler must Accept it for a drop to happen there
+type DragOverEvent struct {
+	DragEvent
+}
+
+func NewDragOverEvent(x, y int, data *DragData) *DragOverEvent {
+	return &DragOverEvent{DragEvent: newDragEvent(EventTypeDragOver, x, y, data)}
+}
+
+// DragLeaveEvent is sent to an element when a drag moves off it and all
+// of its descendants, or ends over it; it does not bubble
+type DragLeaveEvent struct {
+	DragEvent
+}
+
+func NewDragLeaveEvent(x, y int, data *DragData) *DragLeaveEvent {
+	return &DragLeaveEvent{DragEvent: newDragEvent(EventTypeDragLeave, x, y, data)}
+}
+
+// DropEvent is dispatched to the element under the pointer when data is
+// dropped on an element that accepted the last DragOverEvent; backends
+// also send it for files dropped from other applications
+type DropEvent struct {
+	DragEvent
+}
+
+func NewDropEvent(x, y int, data *DragData, effect DropEffect) *DropEvent {
+	event := &DropEvent{DragEvent: newDragEvent(EventTypeDrop, x, y, data)}
+	event.effect = effect
+	return event
+}
+
+// CompositionAttr tells how an input method marks a pre-edit segment
+type CompositionAttr int
+
+const (
+	// CompositionInput is text typed but not yet converted
+	CompositionInput CompositionAttr = iota
+	// CompositionConverted is text already converted, such as kana to kanji
+	CompositionConverted
+	// CompositionTarget is the segment the candidate window is converting
+	CompositionTarget
+)
+
+// CompositionSegment marks a run of pre-edit text, in rune offsets
+type CompositionSegment struct {
+	Start, End int
+	Attr       CompositionAttr
+}
+
+// CompositionStartEvent is sent to the focused element when an input
+// method starts composing text
+type CompositionStartEvent struct {
+	BaseEvent
+}
+
+func NewCompositionStartEvent() *CompositionStartEvent {
+	return &CompositionStartEvent{BaseEvent: NewBaseEvent(EventTypeCompositionStart)}
+}
+
+// CompositionUpdateEvent carries the pre-edit text being composed, the
+// caret position within it in runes and how its segments are marked
+type CompositionUpdateEvent struct {
+	BaseEvent
+	Text     string
+	Cursor   int
+	Segments []CompositionSegment
+}
+
+func NewCompositionUpdateEvent(text string, cursor int, segments []CompositionSegment) *CompositionUpdateEvent {
+	return &CompositionUpdateEvent{
+		BaseEvent: NewBaseEvent(EventTypeCompositionUpdate),
+		Text:      text,
+		Cursor:    cursor,
+		Segments:  segments,
+	}
+}
+
+// CompositionEndEvent is sent when composition finishes or is cancelled;
+// committed text follows as a TextInputEvent
+type CompositionEndEvent struct {
+	BaseEvent
+}
+
+func NewCompositionEndEvent() *CompositionEndEvent {
+	return &CompositionEndEvent{BaseEvent: NewBaseEvent(EventTypeCompositionEnd)}
+}
+
+// TouchPoint is one finger on a touchscreen; IDs stay the same from the
+// touch's start to its end
+type TouchPoint struct {
+	ID       int
+	X, Y     int     // Window coordinates
+	Pressure float64 // 0 to 1, or 0 when the hardware does not report it
+}
+
+// TouchEvent holds what touch events share. Changed lists the points the
+// event is about, and Touches every point on the screen; the window fills
+// in Touches. The position is that of the first changed point.
+type TouchEvent struct {
+	BaseEvent
+	PointerEvent
+	Touches []TouchPoint
+	Changed []TouchPoint
+}
+
+func newTouchEvent(eventType EventType, changed []TouchPoint) TouchEvent {
+	event := TouchEvent{BaseEvent: NewBaseEvent(eventType), Changed: changed}
+	if len(changed) > 0 {
+		event.PointerEvent = newPointerEvent(changed[0].X, changed[0].Y)
+	}
+	return event
+}
+
+// touchEvent exposes the shared fields of touch events for routing
+func (e *TouchEvent) touchEvent() *TouchEvent {
+	return e
+}
+
+// TouchStartEvent is sent when fingers touch the screen; it goes to the
+// element under each new point, which keeps receiving that point's events
+type TouchStartEvent struct {
+	TouchEvent
+}
+
+func NewTouchStartEvent(changed ...TouchPoint) *TouchStartEvent {
+	return &TouchStartEvent{TouchEvent: newTouchEvent(EventTypeTouchStart, changed)}
+}
+
+// TouchMoveEvent is sent when touching fingers move
+type TouchMoveEvent struct {
+	TouchEvent
+}
+
+func NewTouchMoveEvent(changed ...TouchPoint) *TouchMoveEvent {
+	return &TouchMoveEvent{TouchEvent: newTouchEvent(EventTypeTouchMove, changed)}
+}
+
+// TouchEndEvent is sent when fingers leave the screen
+type TouchEndEvent struct {
+	TouchEvent
+}
+
+func NewTouchEndEvent(changed ...TouchPoint) *TouchEndEvent {
+	return &TouchEndEvent{TouchEvent: newTouchEvent(EventTypeTouchEnd, changed)}
+}
+
+// TouchCancelEvent is sent when the system takes touches away, such as
+// for a palm rejection or an edge gesture
+type TouchCancelEvent struct {
+	TouchEvent
+}
+
+func NewTouchCancelEvent(changed ...TouchPoint) *TouchCancelEvent {
+	return &TouchCancelEvent{TouchEvent: newTouchEvent(EventTypeTouchCancel, changed)}
+}
+
 // ResizeEvent represents window resize
 type ResizeEvent struct {
 	BaseEvent
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"time"
@@ -21,32 +22,21 @@ func main() {
 	// Create and configure components
 	setupComponents(window)
 
-	// Show window
-	if err := window.Show(); err != nil {
-		log.Fatalf("Failed to show window: %v", err)
-	}
+	// Handle global events (like window close) that no component claimed
+	window.AddEventHandler(gui.EventTypeKeyPress, gui.EventHandlerFunc(func(event gui.Event) bool {
+		return handleGlobalEvent(window, event)
+	}))
 
-	// Main event loop
-	ticker := time.NewTicker(16 * time.Millisecond) // ~60 FPS
-	defer ticker.Stop()
-
-	for window.IsRunning() {
-		select {
-		case <-ticker.C:
-			// Process events
-			events := window.PollEvents()
-			for _, event := range events {
-				if !window.HandleEvent(event) {
-					// Handle global events (like window close)
-					handleGlobalEvent(window, event)
-				}
-			}
-
-			// Update display
-			if err := window.Update(); err != nil {
-				log.Printf("Render error: %v", err)
-			}
+	// Report slow frames
+	window.SetOnFrame(func(stats gui.FrameStats) {
+		if stats.Duration > 16*time.Millisecond {
+			log.Printf("Frame %d took %v", stats.Frame, stats.Duration)
 		}
+	})
+
+	// Main event loop: blocks while idle and redraws only on changes
+	if err := window.Run(context.Background()); err != nil {
+		log.Fatalf("Run error: %v", err)
 	}
 }
 
@@ -150,13 +140,15 @@ func setupComponents(window *gui.Window) {
 	})
 }
 
-func handleGlobalEvent(window *gui.Window, event gui.Event) {
+func handleGlobalEvent(window *gui.Window, event gui.Event) bool {
 	switch event.Type() {
 	case gui.EventTypeKeyPress:
 		keyEvent := event.(*gui.KeyPressEvent)
 		if keyEvent.Key == gui.KeyEscape {
 			fmt.Println("Escape pressed, closing window...")
 			window.Close()
+			return true
 		}
 	}
+	return false
 }
@@ -0,0 +1,286 @@
+package gui
+
+import (
+	"image"
+	"sort"
+)
+
+// SetFocusable controls whether the element can receive keyboard focus
+func (e *Element) SetFocusable(focusable bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.focusable = focusable
+}
+
+// IsFocusable returns whether the element can receive keyboard focus
+func (e *Element) IsFocusable() bool {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.focusable
+}
+
+// SetTabIndex sets the element's position in Tab order. Positive indices
+// come first in ascending order, zero follows tree order and negative
+// indices are skipped by Tab but can still be focused by clicking.
+func (e *Element) SetTabIndex(index int) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.tabIndex = index
+}
+
+// TabIndex returns the element's position in Tab order
+func (e *Element) TabIndex() int {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.tabIndex
+}
+
+// focusScope confines Tab traversal and click focus to a subtree
+type focusScope struct {
+	root     GUIElement
+	previous GUIElement // Focus to restore when the scope is popped
+}
+
+// FocusManager tracks the single focused element of a window and routes
+// keyboard events to it; it is only used on the UI loop
+type FocusManager struct {
+	window  *Window
+	focused GUIElement
+	scopes  []focusScope
+}
+
+// newFocusManager creates the focus manager for a window
+func newFocusManager(window *Window) *FocusManager {
+	return &FocusManager{window: window}
+}
+
+// FocusManager returns the window's focus manager
+func (w *Window) FocusManager() *FocusManager {
+	return w.focus
+}
+
+// Focused returns the focused element, or nil if nothing has focus
+func (f *FocusManager) Focused() GUIElement {
+	return f.focused
+}
+
+// SetFocus moves focus to element, sending BlurEvent to the previously
+// focused element and FocusEvent to the new one; nil clears focus
+func (f *FocusManager) SetFocus(element GUIElement) {
+	if element == f.focused {
+		return
+	}
+
+	previous := f.focused
+	f.focused = element
+
+	if previous != nil {
+		f.window.deliverTo(previous, NewBlurEvent())
+	}
+	if element != nil {
+		f.window.deliverTo(element, NewFocusEvent())
+	}
+}
+
+// ClearFocus removes focus from the focused element
+func (f *FocusManager) ClearFocus() {
+	f.SetFocus(nil)
+}
+
+// Next moves focus to the next element in Tab order, wrapping around
+func (f *FocusManager) Next() {
+	f.step(1)
+}
+
+// Previous moves focus to the previous element in Tab order, wrapping around
+func (f *FocusManager) Previous() {
+	f.step(-1)
+}
+
+// PushScope confines focus to the subtree of root, as for a modal dialog,
+// and focuses its first element in Tab order
+func (f *FocusManager) PushScope(root GUIElement) {
+	f.scopes = append(f.scopes, focusScope{root: root, previous: f.focused})
+
+	order := f.tabOrder()
+	if len(order) > 0 {
+		f.SetFocus(order[0])
+	} else {
+		f.SetFocus(nil)
+	}
+}
+
+// PopScope ends the innermost focus scope and restores the focus it replaced
+func (f *FocusManager) PopScope() {
+	if len(f.scopes) == 0 {
+		return
+	}
+	scope := f.scopes[len(f.scopes)-1]
+	f.scopes = f.scopes[:len(f.scopes)-1]
+
+	if scope.previous != nil {
+		if _, ok := f.window.Element.pathTo(scope.previous); !ok {
+			scope.previous = nil // It left the tree while the scope was open
+		}
+	}
+	f.SetFocus(scope.previous)
+}
+
+// step moves focus by delta positions in Tab order
+func (f *FocusManager) step(delta int) {
+	order := f.tabOrder()
+	if len(order) == 0 {
+		return
+	}
+
+	current := -1
+	for i, el := range order {
+		if el == f.focused {
+			current = i
+			break
+		}
+	}
+
+	var next int
+	switch {
+	case current < 0 && delta > 0:
+		next = 0
+	case current < 0:
+		next = len(order) - 1
+	default:
+		next = (current + delta + len(order)) % len(order)
+	}
+	f.SetFocus(order[next])
+}
+
+// scopeRoot returns the root of the innermost scope and its base element
+func (f *FocusManager) scopeRoot() (GUIElement, *Element) {
+	if len(f.scopes) > 0 {
+		root := f.scopes[len(f.scopes)-1].root
+		return root, baseOf(root)
+	}
+	return f.window, f.window.Element
+}
+
+// inScope reports whether a path from the window ends inside the current scope
+func (f *FocusManager) inScope(path []GUIElement) bool {
+	if len(f.scopes) == 0 {
+		return true
+	}
+	root, _ := f.scopeRoot()
+	for _, el := range path {
+		if el == root {
+			return true
+		}
+	}
+	return false
+}
+
+// tabOrder lists the focusable elements of the current scope in Tab order
+func (f *FocusManager) tabOrder() []GUIElement {
+	root, rootBase := f.scopeRoot()
+	if rootBase == nil {
+		return nil
+	}
+
+	type candidate struct {
+		element  GUIElement
+		tabIndex int
+	}
+	var candidates []candidate
+
+	var walk func(el GUIElement, base *Element)
+	walk = func(el GUIElement, base *Element) {
+		if !el.IsVisible() {
+			return
+		}
+		if base.IsFocusable() && base.TabIndex() >= 0 {
+			candidates = append(candidates, candidate{el, base.TabIndex()})
+		}
+
+		base.mu.RLock()
+		children := base.snapshotChildren()
+		base.mu.RUnlock()
+
+		for _, child := range children {
+			if childBase := baseOf(child); childBase != nil {
+				walk(child, childBase)
+			}
+		}
+	}
+	walk(root, rootBase)
+
+	// Positive indices first in ascending order, then tree order
+	sort.SliceStable(candidates, func(i, j int) bool {
+		a, b := candidates[i].tabIndex, candidates[j].tabIndex
+		if a > 0 && b > 0 {
+			return a < b
+		}
+		return a > 0 && b == 0
+	})
+
+	order := make([]GUIElement, len(candidates))
+	for i, c := range candidates {
+		order[i] = c.element
+	}
+	return order
+}
+
+// focusAt focuses the innermost focusable element of a pointer path,
+// or clears focus when the press landed on nothing focusable
+func (f *FocusManager) focusAt(path []GUIElement) {
+	if !f.inScope(path) {
+		return // Modal scopes ignore presses outside them
+	}
+
+	for i := len(path) - 1; i >= 0; i-- {
+		if base := baseOf(path[i]); base != nil && base.IsFocusable() {
+			f.SetFocus(path[i])
+			return
+		}
+	}
+	f.SetFocus(nil)
+}
+
+// dispatch delivers a keyboard event to the focused element, bubbling up
+// to the window; without focus the window itself is the target
+func (f *FocusManager) dispatch(event Event) bool {
+	if f.focused != nil {
+		if _, ok := f.window.Element.pathTo(f.focused); !ok {
+			f.focused = nil // The focused element left the tree
+		}
+	}
+	handled := f.window.deliverTo(f.focused, event)
+
+	// Tab traversal is the default action of an unhandled Tab press
+	if keyEvent, ok := event.(*KeyPressEvent); ok && !handled && !event.IsDefaultPrevented() &&
+		keyEvent.Key == KeyTab && !keyEvent.Modifiers.Has(ModifierCtrl) {
+		if keyEvent.Modifiers.Has(ModifierShift) {
+			f.Previous()
+		} else {
+			f.Next()
+		}
+		return true
+	}
+
+	return handled
+}
+
+// TextInputClient is implemented by elements that edit text, so the window
+// can tell the input method where the caret is
+type TextInputClient interface {
+	// CaretRect returns the caret in window coordinates, or an empty
+	// rectangle while the element does not accept text
+	CaretRect() image.Rectangle
+}
+
+// updateTextInputRect reports the focused text field's caret to the renderer
+func (w *Window) updateTextInputRect() {
+	var rect image.Rectangle
+	if client, ok := w.focus.Focused().(TextInputClient); ok {
+		rect = client.CaretRect()
+	}
+	if rect != w.textInput && w.renderer.SetTextInputRect(rect) == nil {
+		w.textInput = rect
+	}
+}
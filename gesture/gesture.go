@@ -0,0 +1,129 @@
+// Package gesture recognizes taps, long presses, pans, pinches and swipes
+// from the touch events of an element.
+package gesture
+
+import (
+	"math"
+
+	"github.com/opd-ai/gui"
+)
+
+// Target is an element gestures can be attached to; components embedding
+// *gui.Element satisfy it
+type Target interface {
+	AddEventHandler(eventType gui.EventType, handler gui.EventHandler)
+	Animate(animation gui.Animation)
+	Window() *gui.Window
+}
+
+// Recognizer follows the touches of one element and calls back when it
+// sees its gesture
+type Recognizer interface {
+	// HandleTouch feeds a touch event and reports whether the recognizer
+	// claims the touch; claimed touches do not become mouse events
+	HandleTouch(target Target, event gui.Event, touch *gui.TouchEvent) bool
+}
+
+// Attach feeds the element's touch events to the recognizers. Every
+// recognizer sees every event, so a tap and a pan can share an element.
+func Attach(target Target, recognizers ...Recognizer) {
+	handler := gui.EventHandlerFunc(func(event gui.Event) bool {
+		touch := touchOf(event)
+		if touch == nil {
+			return false
+		}
+		claimed := false
+		for _, r := range recognizers {
+			if r.HandleTouch(target, event, touch) {
+				claimed = true
+			}
+		}
+		return claimed
+	})
+
+	target.AddEventHandler(gui.EventTypeTouchStart, handler)
+	target.AddEventHandler(gui.EventTypeTouchMove, handler)
+	target.AddEventHandler(gui.EventTypeTouchEnd, handler)
+	target.AddEventHandler(gui.EventTypeTouchCancel, handler)
+}
+
+// touchOf returns the shared fields of a touch event, or nil
+func touchOf(event gui.Event) *gui.TouchEvent {
+	switch ev := event.(type) {
+	case *gui.TouchStartEvent:
+		return &ev.TouchEvent
+	case *gui.TouchMoveEvent:
+		return &ev.TouchEvent
+	case *gui.TouchEndEvent:
+		return &ev.TouchEvent
+	case *gui.TouchCancelEvent:
+		return &ev.TouchEvent
+	}
+	return nil
+}
+
+// phase is the stage of a touch an event reports
+type phase int
+
+const (
+	began phase = iota
+	moved
+	ended
+	cancelled
+)
+
+// phaseOf returns the stage of a touch event
+func phaseOf(event gui.Event) phase {
+	switch event.(type) {
+	case *gui.TouchStartEvent:
+		return began
+	case *gui.TouchMoveEvent:
+		return moved
+	case *gui.TouchEndEvent:
+		return ended
+	}
+	return cancelled
+}
+
+// points tracks the fingers on one element, in the order they landed
+type points []gui.TouchPoint
+
+// update applies the changed points of an event
+func (p *points) update(ph phase, changed []gui.TouchPoint) {
+	for _, point := range changed {
+		index := p.index(point.ID)
+		switch {
+		case ph == began && index < 0:
+			*p = append(*p, point)
+		case ph == moved && index >= 0:
+			(*p)[index] = point
+		case (ph == ended || ph == cancelled) && index >= 0:
+			*p = append((*p)[:index], (*p)[index+1:]...)
+		}
+	}
+}
+
+// index returns the position of a touch ID, or -1
+func (p points) index(id int) int {
+	for i, point := range p {
+		if point.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// find returns the changed point with the given ID
+func find(changed []gui.TouchPoint, id int) (gui.TouchPoint, bool) {
+	for _, point := range changed {
+		if point.ID == id {
+			return point, true
+		}
+	}
+	return gui.TouchPoint{}, false
+}
+
+// distance returns the distance between two positions
+func distance(x1, y1, x2, y2 int) float64 {
+	return math.Hypot(float64(x2-x1), float64(y2-y1))
+}
@@ -0,0 +1,185 @@
+package gesture
+
+import (
+	"math"
+	"time"
+
+	"github.com/opd-ai/gui"
+)
+
+// Thresholds for pans and swipes
+const (
+	// PanSlop is how far, in pixels, a finger must move before a pan starts
+	PanSlop = 8
+	// SwipeMinDistance is the shortest movement, in pixels, that swipes
+	SwipeMinDistance = 50
+	// SwipeMaxDuration is the longest a swipe may take
+	SwipeMaxDuration = 500 * time.Millisecond
+)
+
+// Pan recognizes a single finger dragging across the element. Velocity is
+// reported in pixels per second so scrolling can continue with inertia.
+type Pan struct {
+	OnPanStart func(x, y int)
+	OnPan      func(dx, dy int)
+	OnPanEnd   func(vx, vy float64)
+
+	tracking bool
+	panning  bool
+	id       int
+	startX   int
+	startY   int
+	lastX    int
+	lastY    int
+	last     time.Time
+	vx, vy   float64
+}
+
+// NewPan creates a pan recognizer
+func NewPan(onPan func(dx, dy int)) *Pan {
+	return &Pan{OnPan: onPan}
+}
+
+// IsPanning reports whether a pan is in progress
+func (p *Pan) IsPanning() bool {
+	return p.panning
+}
+
+// HandleTouch follows a single finger, reporting movement once it passes PanSlop
+func (p *Pan) HandleTouch(target Target, event gui.Event, touch *gui.TouchEvent) bool {
+	switch phaseOf(event) {
+	case began:
+		if p.tracking {
+			return p.panning
+		}
+		point := touch.Changed[0]
+		p.tracking = true
+		p.panning = false
+		p.id = point.ID
+		p.startX, p.startY = point.X, point.Y
+		p.lastX, p.lastY = point.X, point.Y
+		p.last = event.Timestamp()
+		p.vx, p.vy = 0, 0
+		return false // Claimed only once it moves, so a still press can still click
+
+	case moved:
+		point, ok := find(touch.Changed, p.id)
+		if !p.tracking || !ok {
+			return p.panning
+		}
+		if !p.panning {
+			if distance(p.startX, p.startY, point.X, point.Y) < PanSlop {
+				return false
+			}
+			p.panning = true
+			if p.OnPanStart != nil {
+				p.OnPanStart(p.startX, p.startY)
+			}
+		}
+
+		dx, dy := point.X-p.lastX, point.Y-p.lastY
+		now := event.Timestamp()
+		if dt := now.Sub(p.last).Seconds(); dt > 0 {
+			// Smooth the velocity so one jittery sample does not dominate
+			p.vx = 0.8*float64(dx)/dt + 0.2*p.vx
+			p.vy = 0.8*float64(dy)/dt + 0.2*p.vy
+		}
+		p.lastX, p.lastY, p.last = point.X, point.Y, now
+
+		if p.OnPan != nil && (dx != 0 || dy != 0) {
+			p.OnPan(dx, dy)
+		}
+		return true
+
+	case ended, cancelled:
+		if _, ok := find(touch.Changed, p.id); !p.tracking || !ok {
+			return p.panning
+		}
+		wasPanning := p.panning
+		p.tracking, p.panning = false, false
+		if wasPanning && p.OnPanEnd != nil {
+			if phaseOf(event) == cancelled {
+				p.OnPanEnd(0, 0)
+			} else {
+				p.OnPanEnd(p.vx, p.vy)
+			}
+		}
+		return wasPanning
+	}
+	return false
+}
+
+// Direction is the way a swipe went
+type Direction int
+
+const (
+	DirectionLeft Direction = iota
+	DirectionRight
+	DirectionUp
+	DirectionDown
+)
+
+// Swipe recognizes a quick single finger flick in one direction
+type Swipe struct {
+	OnSwipe func(direction Direction)
+
+	tracking bool
+	id       int
+	startX   int
+	startY   int
+	start    time.Time
+}
+
+// NewSwipe creates a swipe recognizer
+func NewSwipe(onSwipe func(direction Direction)) *Swipe {
+	return &Swipe{OnSwipe: onSwipe}
+}
+
+// HandleTouch measures a finger from landing to lifting and swipes when it
+// moved far and fast enough, mostly along one axis
+func (s *Swipe) HandleTouch(target Target, event gui.Event, touch *gui.TouchEvent) bool {
+	switch phaseOf(event) {
+	case began:
+		if s.tracking || len(touch.Touches) != 1 {
+			s.tracking = false
+			return false
+		}
+		point := touch.Changed[0]
+		s.tracking = true
+		s.id = point.ID
+		s.startX, s.startY = point.X, point.Y
+		s.start = event.Timestamp()
+
+	case ended:
+		point, ok := find(touch.Changed, s.id)
+		if !s.tracking || !ok {
+			return false
+		}
+		s.tracking = false
+
+		dx, dy := float64(point.X-s.startX), float64(point.Y-s.startY)
+		if event.Timestamp().Sub(s.start) > SwipeMaxDuration ||
+			math.Hypot(dx, dy) < SwipeMinDistance || s.OnSwipe == nil {
+			return false
+		}
+
+		// Diagonal flicks are ambiguous, so one axis must clearly dominate
+		switch {
+		case math.Abs(dx) >= 2*math.Abs(dy) && dx < 0:
+			s.OnSwipe(DirectionLeft)
+		case math.Abs(dx) >= 2*math.Abs(dy):
+			s.OnSwipe(DirectionRight)
+		case math.Abs(dy) >= 2*math.Abs(dx) && dy < 0:
+			s.OnSwipe(DirectionUp)
+		case math.Abs(dy) >= 2*math.Abs(dx):
+			s.OnSwipe(DirectionDown)
+		default:
+			return false
+		}
+		return true
+
+	case cancelled:
+		s.tracking = false
+	}
+	return false
+}
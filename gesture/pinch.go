@@ -0,0 +1,59 @@
+package gesture
+
+import "github.com/opd-ai/gui"
+
+// Pinch recognizes two fingers moving apart or together. Scale is the
+// current finger distance over the distance when the pinch started.
+type Pinch struct {
+	OnPinchStart func(centerX, centerY int)
+	OnPinch      func(scale float64, centerX, centerY int)
+	OnPinchEnd   func(scale float64)
+
+	fingers  points
+	pinching bool
+	initial  float64
+	scale    float64
+}
+
+// NewPinch creates a pinch recognizer
+func NewPinch(onPinch func(scale float64, centerX, centerY int)) *Pinch {
+	return &Pinch{OnPinch: onPinch}
+}
+
+// HandleTouch starts a pinch when a second finger lands and reports the
+// scale as either finger moves
+func (p *Pinch) HandleTouch(target Target, event gui.Event, touch *gui.TouchEvent) bool {
+	ph := phaseOf(event)
+	p.fingers.update(ph, touch.Changed)
+
+	switch {
+	case !p.pinching && len(p.fingers) == 2 && ph == began:
+		a, b := p.fingers[0], p.fingers[1]
+		p.initial = distance(a.X, a.Y, b.X, b.Y)
+		if p.initial == 0 {
+			return false
+		}
+		p.pinching = true
+		p.scale = 1
+		if p.OnPinchStart != nil {
+			p.OnPinchStart((a.X+b.X)/2, (a.Y+b.Y)/2)
+		}
+		return true
+
+	case p.pinching && len(p.fingers) == 2 && ph == moved:
+		a, b := p.fingers[0], p.fingers[1]
+		p.scale = distance(a.X, a.Y, b.X, b.Y) / p.initial
+		if p.OnPinch != nil {
+			p.OnPinch(p.scale, (a.X+b.X)/2, (a.Y+b.Y)/2)
+		}
+		return true
+
+	case p.pinching && len(p.fingers) != 2:
+		p.pinching = false
+		if p.OnPinchEnd != nil {
+			p.OnPinchEnd(p.scale)
+		}
+		return true
+	}
+	return p.pinching
+}
@@ -0,0 +1,134 @@
+package gesture
+
+import (
+	"time"
+
+	"github.com/opd-ai/gui"
+)
+
+// Thresholds for taps and long presses
+const (
+	// TapSlop is how far, in pixels, a finger may wander and still tap
+	TapSlop = 10
+	// TapTimeout is the longest a finger may stay down for a tap
+	TapTimeout = 300 * time.Millisecond
+	// LongPressDuration is how long a finger must stay still for a long press
+	LongPressDuration = 500 * time.Millisecond
+)
+
+// Tap recognizes a single finger briefly touching the element
+type Tap struct {
+	OnTap func(x, y int)
+
+	tracking bool
+	id       int
+	startX   int
+	startY   int
+	start    time.Time
+}
+
+// NewTap creates a tap recognizer
+func NewTap(onTap func(x, y int)) *Tap {
+	return &Tap{OnTap: onTap}
+}
+
+// HandleTouch follows a single finger and taps when it lifts quickly in place
+func (t *Tap) HandleTouch(target Target, event gui.Event, touch *gui.TouchEvent) bool {
+	switch phaseOf(event) {
+	case began:
+		if t.tracking || len(touch.Touches) != 1 {
+			t.tracking = false // A second finger is not a tap
+			return false
+		}
+		point := touch.Changed[0]
+		t.tracking = true
+		t.id = point.ID
+		t.startX, t.startY = point.X, point.Y
+		t.start = event.Timestamp()
+		return true
+
+	case moved:
+		if point, ok := find(touch.Changed, t.id); t.tracking && ok &&
+			distance(t.startX, t.startY, point.X, point.Y) > TapSlop {
+			t.tracking = false
+		}
+
+	case ended:
+		point, ok := find(touch.Changed, t.id)
+		if !t.tracking || !ok {
+			return false
+		}
+		t.tracking = false
+		if event.Timestamp().Sub(t.start) <= TapTimeout && t.OnTap != nil {
+			t.OnTap(point.X, point.Y)
+			return true
+		}
+
+	case cancelled:
+		t.tracking = false
+	}
+	return t.tracking
+}
+
+// LongPress recognizes a single finger held still on the element
+type LongPress struct {
+	OnLongPress func(x, y int)
+	Duration    time.Duration
+
+	tracking bool
+	id       int
+	x, y     int
+	timer    *gui.Timer // Fires the long press unless the finger moves or lifts first
+}
+
+// NewLongPress creates a long press recognizer using LongPressDuration
+func NewLongPress(onLongPress func(x, y int)) *LongPress {
+	return &LongPress{OnLongPress: onLongPress, Duration: LongPressDuration}
+}
+
+// HandleTouch starts timing a still finger and fires once it was held long enough
+func (l *LongPress) HandleTouch(target Target, event gui.Event, touch *gui.TouchEvent) bool {
+	switch phaseOf(event) {
+	case began:
+		if l.tracking || len(touch.Touches) != 1 {
+			l.cancel()
+			return false
+		}
+		window := target.Window()
+		if window == nil {
+			return false // Timing needs the window's timers
+		}
+		point := touch.Changed[0]
+		l.tracking = true
+		l.id = point.ID
+		l.x, l.y = point.X, point.Y
+		l.timer = window.AfterFunc(l.Duration, func() {
+			l.tracking, l.timer = false, nil
+			if l.OnLongPress != nil {
+				l.OnLongPress(l.x, l.y)
+			}
+		})
+		return true
+
+	case moved:
+		if point, ok := find(touch.Changed, l.id); l.tracking && ok &&
+			distance(l.x, l.y, point.X, point.Y) > TapSlop {
+			l.cancel()
+		}
+
+	case ended, cancelled:
+		if _, ok := find(touch.Changed, l.id); ok {
+			l.cancel()
+		}
+	}
+	return l.tracking
+}
+
+// cancel abandons the press in progress
+func (l *LongPress) cancel() {
+	l.tracking = false
+	if l.timer != nil {
+		l.timer.Stop()
+		l.timer = nil
+	}
+}
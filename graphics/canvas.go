@@ -13,6 +13,15 @@ type GGCanvas struct {
 	context *gg.Context
 	width   int
 	height  int
+	clips   [][4]gg.Point // Clipping rectangles in device coordinates, intersected
+	clipped bool          // Clipping changed since the last Push
+	saved   []clipState   // Clipping saved by Push
+}
+
+// clipState is the clipping saved by a Push, which gg's own Pop leaves alone
+type clipState struct {
+	clips   [][4]gg.Point
+	clipped bool
 }
 
 // NewGGCanvas creates a new canvas using gg
@@ -87,8 +96,15 @@ func (c *GGCanvas) DrawImage(img image.Image, x, y, width, height int) error {
 	return nil
 }
 
-// SetClippingRegion sets a clipping rectangle
+// SetClippingRegion sets a clipping rectangle, within any already set
 func (c *GGCanvas) SetClippingRegion(x, y, width, height int) {
+	var corners [4]gg.Point
+	for i, p := range [4][2]int{{x, y}, {x + width, y}, {x + width, y + height}, {x, y + height}} {
+		corners[i].X, corners[i].Y = c.context.TransformPoint(float64(p[0]), float64(p[1]))
+	}
+	c.clips = append(c.clips[:len(c.clips):len(c.clips)], corners) // Never writes into a saved list
+	c.clipped = true
+
 	c.context.DrawRectangle(float64(x), float64(y), float64(width), float64(height))
 	c.context.Clip()
 }
@@ -96,6 +112,64 @@ func (c *GGCanvas) SetClippingRegion(x, y, width, height int) {
 // ClearClippingRegion removes the current clipping region
 func (c *GGCanvas) ClearClippingRegion() {
 	c.context.ResetClip()
+	c.clips = nil
+	c.clipped = true
+}
+
+// Push saves the current transform and clipping region
+func (c *GGCanvas) Push() {
+	c.context.Push()
+	c.saved = append(c.saved, clipState{clips: c.clips, clipped: c.clipped})
+	c.clipped = false
+}
+
+// Pop restores the transform and clipping region saved by the matching Push
+func (c *GGCanvas) Pop() {
+	c.context.Pop()
+	if len(c.saved) == 0 {
+		return
+	}
+	state := c.saved[len(c.saved)-1]
+	c.saved = c.saved[:len(c.saved)-1]
+	if c.clipped {
+		c.applyClips(state.clips)
+	}
+	c.clips, c.clipped = state.clips, state.clipped
+}
+
+// applyClips replaces the clipping mask with the intersection of clipping
+// rectangles given in device coordinates
+func (c *GGCanvas) applyClips(clips [][4]gg.Point) {
+	c.context.ResetClip()
+	if len(clips) == 0 {
+		return
+	}
+	c.context.Push()
+	c.context.Identity()
+	for _, corners := range clips {
+		c.context.MoveTo(corners[0].X, corners[0].Y)
+		for _, p := range corners[1:] {
+			c.context.LineTo(p.X, p.Y)
+		}
+		c.context.ClosePath()
+		c.context.Clip()
+	}
+	c.context.Pop() // gg's Pop restores the transform but keeps the mask
+}
+
+// Translate moves the origin of later drawing
+func (c *GGCanvas) Translate(dx, dy float64) {
+	c.context.Translate(dx, dy)
+}
+
+// Scale scales later drawing about the origin
+func (c *GGCanvas) Scale(sx, sy float64) {
+	c.context.Scale(sx, sy)
+}
+
+// Rotate rotates later drawing about the origin by an angle in radians
+func (c *GGCanvas) Rotate(radians float64) {
+	c.context.Rotate(radians)
 }
 
 // Clear fills the entire canvas with the specified color
@@ -106,8 +180,8 @@ func (c *GGCanvas) Clear(bgColor colorful.Color) error {
 	return nil
 }
 
-// Present displays the rendered content (placeholder for platform-specific implementation)
-func (c *GGCanvas) Present() error {
+// Present displays the damaged region of the rendered content (placeholder for platform-specific implementation)
+func (c *GGCanvas) Present(damage image.Rectangle) error {
 	// This will be implemented in platform-specific renderers
 	return nil
 }
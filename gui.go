@@ -4,6 +4,7 @@ package gui
 import (
 	"image"
 	"sync"
+	"time"
 
 	"github.com/lucasb-eyer/go-colorful"
 	"golang.org/x/image/font"
@@ -42,59 +43,113 @@ type Canvas interface {
 	// Image operations
 	DrawImage(img image.Image, x, y, width, height int) error
 
-	// Clipping and transformations
+	// Clipping and transformations; Push saves the transform and clipping
+	// region and Pop restores them
 	SetClippingRegion(x, y, width, height int)
 	ClearClippingRegion()
+	Push()
+	Pop()
+	Translate(dx, dy float64)
+	Scale(sx, sy float64)
+	Rotate(radians float64)
 
 	// Canvas management
 	Clear(color colorful.Color) error
-	Present() error
+	Present(damage image.Rectangle) error
 }
 
 // Element provides base implementation for GUI elements
 type Element struct {
-	mu       sync.RWMutex
-	x, y     int
-	width    int
-	height   int
-	visible  bool
-	parent   GUIElement
-	children []GUIElement
-	handlers map[EventType][]EventHandler
+	mu              sync.RWMutex
+	x, y            int
+	width           int
+	height          int
+	visible         bool
+	parent          *Element   // Base of the element this one is a child of
+	owner           GUIElement // Outermost value embedding this element, as its parent stores it
+	children        []GUIElement
+	handlers        map[EventType][]EventHandler
+	captureHandlers map[EventType][]EventHandler // Run on the way down to an event's target
+	dirty           []image.Rectangle
+	animations      []Animation
+	focusable       bool
+	tabIndex        int
+	cursor          CursorShape
+	dragSource      DragSourceFunc
+	scaleX, scaleY  float64 // Scale about the center, 1 for none
+	rotation        float64 // Rotation about the center in radians
+	layout          Layout
+	minSize         Size
+	maxSize         Size // Zero dimensions are unlimited
+	preferredSize   Size // Zero dimensions are measured
+	naturalSize     Size // Size last set by hand, kept when layouts resize the element
+	margin          Insets
+	padding         Insets
+	needsLayout     bool
 }
 
 // NewElement creates a new base element
 func NewElement(x, y, width, height int) *Element {
 	return &Element{
-		x:        x,
-		y:        y,
-		width:    width,
-		height:   height,
-		visible:  true,
-		children: make([]GUIElement, 0),
-		handlers: make(map[EventType][]EventHandler),
+		x:               x,
+		y:               y,
+		width:           width,
+		height:          height,
+		visible:         true,
+		children:        make([]GUIElement, 0),
+		handlers:        make(map[EventType][]EventHandler),
+		captureHandlers: make(map[EventType][]EventHandler),
+		scaleX:          1,
+		scaleY:          1,
+		naturalSize:     Size{Width: width, Height: height},
 	}
 }
 
-// GetBounds returns the element's bounds
+// GetBounds returns the element's position, relative to its parent, and size
 func (e *Element) GetBounds() (x, y, width, height int) {
 	e.mu.RLock()
 	defer e.mu.RUnlock()
 	return e.x, e.y, e.width, e.height
 }
 
-// SetPosition updates the element's position
+// SetPosition moves the element, relative to its parent's top-left corner
 func (e *Element) SetPosition(x, y int) {
 	e.mu.Lock()
 	defer e.mu.Unlock()
+	if e.x == x && e.y == y {
+		return
+	}
+	e.invalidateLocked(e.visualBoundsLocked())
 	e.x, e.y = x, y
+	e.invalidateLocked(e.visualBoundsLocked())
 }
 
-// SetSize updates the element's dimensions
+// SetSize updates the element's dimensions, which layouts also take as its
+// natural size when it has no layout or measurable content
 func (e *Element) SetSize(width, height int) {
 	e.mu.Lock()
-	defer e.mu.Unlock()
+	e.naturalSize = Size{Width: width, Height: height}
+	e.mu.Unlock()
+	e.resize(width, height)
+}
+
+// resize changes the element's dimensions without touching its natural size
+func (e *Element) resize(width, height int) {
+	e.mu.Lock()
+	if e.width == width && e.height == height {
+		e.mu.Unlock()
+		return
+	}
+	e.invalidateLocked(e.visualBoundsLocked())
 	e.width, e.height = width, height
+	e.invalidateLocked(e.visualBoundsLocked())
+	arranges := e.layout != nil
+	e.mu.Unlock()
+
+	// The children have a new area to fill
+	if arranges {
+		e.InvalidateLayout()
+	}
 }
 
 // IsVisible returns visibility state
@@ -107,48 +162,184 @@ func (e *Element) IsVisible() bool {
 // SetVisible controls visibility
 func (e *Element) SetVisible(visible bool) {
 	e.mu.Lock()
-	defer e.mu.Unlock()
+	if e.visible == visible {
+		e.mu.Unlock()
+		return
+	}
 	e.visible = visible
+	e.invalidateLocked(e.visualBoundsLocked())
+	e.mu.Unlock()
+
+	// Hidden children take no space, so the parent lays out again
+	e.InvalidateLayout()
 }
 
-// AddChild adds a child element
+// AddChild appends a child element, detaching it from its previous parent
 func (e *Element) AddChild(child GUIElement) {
-	e.mu.Lock()
-	defer e.mu.Unlock()
-	e.children = append(e.children, child)
+	e.insertChild(-1, child)
 }
 
-// RemoveChild removes a child element
+// RemoveChild removes a child element, letting a layout that keeps
+// settings per child forget it
 func (e *Element) RemoveChild(child GUIElement) {
+	if !e.removeChild(child) {
+		return
+	}
+	if layout, ok := e.Layout().(ChildRemovedLayout); ok {
+		layout.ChildRemoved(child)
+	}
+}
+
+// removeChild takes a child out of the child list and reports whether it was there
+func (e *Element) removeChild(child GUIElement) bool {
 	e.mu.Lock()
-	defer e.mu.Unlock()
+	removed := false
 	for i, c := range e.children {
 		if c == child {
 			e.children = append(e.children[:i], e.children[i+1:]...)
+			// The child leaves the tree, so its area is repainted on our behalf
+			e.invalidateLocked(e.toParentLocked().TransformRect(visualBoundsOf(child)))
+			if base := baseOf(child); base != nil {
+				base.setParent(nil)
+			}
+			removed = true
 			break
 		}
 	}
+	e.mu.Unlock()
+
+	if removed {
+		e.InvalidateLayout()
+	}
+	return removed
+}
+
+// Invalidate marks the element's whole area as needing a repaint; changes
+// to the size of its content also call InvalidateLayout
+func (e *Element) Invalidate() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.invalidateLocked(e.visualBoundsLocked())
 }
 
-// ContainsPoint checks if a point is within the element's bounds
+// InvalidateRect marks a rectangle, relative to the element's top-left
+// corner, as needing a repaint
+func (e *Element) InvalidateRect(x, y, width, height int) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.invalidateLocked(e.toParentLocked().TransformRect(image.Rect(x, y, x+width, y+height)))
+}
+
+// IsDirty reports whether the element or any descendant awaits a repaint
+func (e *Element) IsDirty() bool {
+	e.mu.RLock()
+	if len(e.dirty) > 0 {
+		e.mu.RUnlock()
+		return true
+	}
+	children := e.snapshotChildren()
+	e.mu.RUnlock()
+
+	for _, child := range children {
+		if base := baseOf(child); base != nil && base.IsDirty() {
+			return true
+		}
+	}
+	return false
+}
+
+// takeDamage removes the pending damage of the element and its descendants
+// and returns the union of it, in the parent's coordinates
+func (e *Element) takeDamage() image.Rectangle {
+	e.mu.Lock()
+	var damage image.Rectangle
+	for _, r := range e.dirty {
+		damage = damage.Union(r)
+	}
+	e.dirty = e.dirty[:0]
+	children := e.snapshotChildren()
+	toParent := e.toParentLocked()
+	e.mu.Unlock()
+
+	// Children report damage in our coordinates
+	var childDamage image.Rectangle
+	for _, child := range children {
+		if base := baseOf(child); base != nil {
+			childDamage = childDamage.Union(base.takeDamage())
+		}
+	}
+	return damage.Union(toParent.TransformRect(childDamage))
+}
+
+// snapshotChildren copies the child list so it can be walked without
+// holding the lock; the caller must hold e.mu
+func (e *Element) snapshotChildren() []GUIElement {
+	children := make([]GUIElement, len(e.children))
+	copy(children, e.children)
+	return children
+}
+
+// invalidateLocked records a damaged rectangle; the caller must hold e.mu
+func (e *Element) invalidateLocked(r image.Rectangle) {
+	if r.Empty() {
+		return
+	}
+	e.dirty = append(e.dirty, r)
+}
+
+// element returns the embedded base element; components inherit it by embedding *Element
+func (e *Element) element() *Element {
+	return e
+}
+
+// baseOf returns the base element behind a GUIElement, or nil for
+// implementations that do not embed *Element
+func baseOf(el GUIElement) *Element {
+	if b, ok := el.(interface{ element() *Element }); ok {
+		return b.element()
+	}
+	return nil
+}
+
+// boundsOf returns a GUIElement's bounds as a rectangle
+func boundsOf(el GUIElement) image.Rectangle {
+	x, y, width, height := el.GetBounds()
+	return image.Rect(x, y, x+width, y+height)
+}
+
+// ContainsPoint checks if a point, in window coordinates, is within the
+// element's area after scaling and rotation
 func (e *Element) ContainsPoint(x, y int) bool {
+	lx, ly := e.WindowToLocal(x, y)
+	return e.containsLocal(lx, ly)
+}
+
+// containsLocal checks if a point relative to the element's top-left corner is inside it
+func (e *Element) containsLocal(x, y int) bool {
 	e.mu.RLock()
 	defer e.mu.RUnlock()
-	return x >= e.x && x < e.x+e.width && y >= e.y && y < e.y+e.height
+	return x >= 0 && x < e.width && y >= 0 && y < e.height
 }
 
-// Render provides default rendering for child elements
+// Render provides default rendering for child elements, translating the
+// canvas so children draw at positions relative to this element
 func (e *Element) Render(canvas Canvas) error {
 	e.mu.RLock()
-	defer e.mu.RUnlock()
-
 	if !e.visible {
+		e.mu.RUnlock()
 		return nil
 	}
+	children := e.snapshotChildren()
+	x, y := e.x, e.y
+	e.mu.RUnlock()
+
+	canvas.Push()
+	defer canvas.Pop()
+	canvas.Translate(float64(x), float64(y))
 
 	// Render all children
-	for _, child := range e.children {
-		if err := child.Render(canvas); err != nil {
+	for _, child := range children {
+		if err := renderChild(canvas, child); err != nil {
 			return err
 		}
 	}
@@ -156,29 +347,49 @@ func (e *Element) Render(canvas Canvas) error {
 	return nil
 }
 
-// HandleEvent processes events and propagates to children
+// HandleEvent processes events and propagates to children. Pointer events
+// travel to the deepest element under the pointer through capture, target
+// and bubble phases; other events are offered to children first.
 func (e *Element) HandleEvent(event Event) bool {
-	e.mu.RLock()
-
 	// Check if event is within bounds for position-based events
-	if posEvent, ok := event.(*ClickEvent); ok {
-		if !e.ContainsPoint(posEvent.X, posEvent.Y) {
-			e.mu.RUnlock()
+	if posEvent, ok := event.(PositionedEvent); ok && routesByPosition(event) {
+		lx, ly := e.WindowToLocal(posEvent.Position())
+		if !e.containsLocal(lx, ly) {
 			return false
 		}
+		return dispatchAlong(append([]GUIElement{e}, e.pathAt(lx, ly)...), event)
 	}
 
+	e.mu.RLock()
+	children := e.snapshotChildren()
+	e.mu.RUnlock()
+
 	// Try children first (reverse order for proper z-order)
-	for i := len(e.children) - 1; i >= 0; i-- {
-		if e.children[i].HandleEvent(event) {
-			e.mu.RUnlock()
+	for i := len(children) - 1; i >= 0; i-- {
+		if children[i].HandleEvent(event) {
 			return true
 		}
 	}
 
+	return e.handleOwn(event)
+}
+
+// handleOwn runs the element's own handlers for an event, skipping children
+func (e *Element) handleOwn(event Event) bool {
+	return e.runHandlers(e.handlers, event)
+}
+
+// handleCapture runs the element's capture handlers for an event
+func (e *Element) handleCapture(event Event) bool {
+	return e.runHandlers(e.captureHandlers, event)
+}
+
+// runHandlers executes the handlers registered for the event's type
+func (e *Element) runHandlers(registry map[EventType][]EventHandler, event Event) bool {
 	// Fix: Copy handlers slice to avoid holding lock during handler execution
+	e.mu.RLock()
 	var handlersCopy []EventHandler
-	if handlers, exists := e.handlers[event.Type()]; exists {
+	if handlers, exists := registry[event.Type()]; exists {
 		handlersCopy = make([]EventHandler, len(handlers))
 		copy(handlersCopy, handlers)
 	}
@@ -194,6 +405,16 @@ func (e *Element) HandleEvent(event Event) bool {
 	return false
 }
 
+// routesByPosition reports whether an event goes to the element under the
+// pointer; drags and hover changes are delivered to specific elements instead
+func routesByPosition(event Event) bool {
+	switch event.(type) {
+	case *MouseDragEvent, *MouseEnterEvent, *MouseLeaveEvent, *DragEnterEvent, *DragLeaveEvent:
+		return false
+	}
+	return true
+}
+
 // AddEventHandler registers an event handler
 func (e *Element) AddEventHandler(eventType EventType, handler EventHandler) {
 	e.mu.Lock()
@@ -204,11 +425,30 @@ func (e *Element) AddEventHandler(eventType EventType, handler EventHandler) {
 // Window represents the main application window
 type Window struct {
 	*Element
-	title    string
-	canvas   Canvas
-	renderer Renderer
-	running  bool
-	mu       sync.RWMutex
+	title      string
+	canvas     Canvas
+	renderer   Renderer
+	running    bool
+	background colorful.Color
+	fullRedraw bool
+	maxFPS     int
+	frameFuncs []FrameFunc
+	lastFrame  FrameStats
+	onFrame    func(stats FrameStats)
+	closed     chan struct{}
+	posted     []func()
+	postMu     sync.Mutex
+	timers     []*Timer
+	pointer    pointerState
+	hovered    []GUIElement // Elements under the pointer, outermost first
+	cursor     CursorShape
+	focus      *FocusManager
+	clipboard  Clipboard
+	textInput  image.Rectangle // Caret rectangle last reported to the renderer
+	overlay    *Element        // Drawn above the tree and ignored by pointer routing
+	drag       *dragSession    // Drag and drop in progress, if any
+	touch      touchState
+	mu         sync.RWMutex
 }
 
 // NewWindow creates a new application window
@@ -217,19 +457,35 @@ func NewWindow(title string, width, height int) (*Window, error) {
 	if err != nil {
 		return nil, err
 	}
+	return newWindow(title, renderer, width, height)
+}
 
+// newWindow creates a window drawn by renderer
+func newWindow(title string, renderer Renderer, width, height int) (*Window, error) {
 	canvas, err := renderer.CreateCanvas()
 	if err != nil {
 		return nil, err
 	}
 
-	return &Window{
-		Element:  NewElement(0, 0, width, height),
-		title:    title,
-		canvas:   canvas,
-		renderer: renderer,
-		running:  false,
-	}, nil
+	window := &Window{
+		Element:    NewElement(0, 0, width, height),
+		title:      title,
+		canvas:     canvas,
+		renderer:   renderer,
+		running:    false,
+		background: colorful.Color{R: 1.0, G: 1.0, B: 1.0},
+		fullRedraw: true,
+		maxFPS:     DefaultMaxFPS,
+		overlay:    NewElement(0, 0, width, height),
+	}
+	window.Element.owner = window
+	window.focus = newFocusManager(window)
+	window.clipboard = renderer.Clipboard()
+	if window.clipboard == nil {
+		window.clipboard = localClipboard
+	}
+
+	return window, nil
 }
 
 // Show displays the window
@@ -238,6 +494,8 @@ func (w *Window) Show() error {
 	defer w.mu.Unlock()
 
 	w.running = true
+	w.fullRedraw = true
+	w.closed = make(chan struct{})
 	return w.renderer.Show(w.title)
 }
 
@@ -246,7 +504,11 @@ func (w *Window) Close() error {
 	w.mu.Lock()
 	defer w.mu.Unlock()
 
+	if w.running {
+		close(w.closed) // Release pending Invoke calls
+	}
 	w.running = false
+	w.renderer.Wake() // Let a blocked Run observe the close
 	return w.renderer.Close()
 }
 
@@ -257,28 +519,135 @@ func (w *Window) IsRunning() bool {
 	return w.running
 }
 
-// Update renders the window contents
-func (w *Window) Update() error {
+// Invalidate schedules a repaint of the entire window
+func (w *Window) Invalidate() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.fullRedraw = true
+}
+
+// IsDirty reports whether any part of the window awaits a repaint
+func (w *Window) IsDirty() bool {
 	w.mu.RLock()
-	defer w.mu.RUnlock()
+	full := w.fullRedraw
+	w.mu.RUnlock()
+	return full || w.Element.IsDirty() || w.overlay.IsDirty() || w.Element.NeedsLayout()
+}
+
+// Update runs closures queued by Post, advances animations and lays out
+// invalidated elements, then repaints the damaged parts of the window contents
+func (w *Window) Update() error {
+	w.runPosted()
+	w.runFrameFuncs(time.Now())
+	w.Element.UpdateLayout()
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
 
 	if !w.running {
 		return nil
 	}
 
-	// Clear canvas
-	w.canvas.Clear(colorful.Color{R: 1.0, G: 1.0, B: 1.0})
+	_, _, width, height := w.GetBounds()
+	windowRect := image.Rect(0, 0, width, height)
+
+	// Collect damage before deciding what to repaint
+	damage := w.Element.takeDamage().Union(w.overlay.takeDamage())
+	if w.fullRedraw {
+		damage = windowRect
+		w.fullRedraw = false
+	}
+	damage = damage.Intersect(windowRect)
+	if damage.Empty() {
+		return nil
+	}
 
-	// Render all elements
+	// Clear the damaged area, restricting all drawing to it
+	if damage == windowRect {
+		if err := w.canvas.Clear(w.background); err != nil {
+			return err
+		}
+	} else {
+		w.canvas.SetClippingRegion(damage.Min.X, damage.Min.Y, damage.Dx(), damage.Dy())
+		defer w.canvas.ClearClippingRegion()
+		if err := w.canvas.DrawRectangle(damage.Min.X, damage.Min.Y, damage.Dx(), damage.Dy(), w.background, true); err != nil {
+			return err
+		}
+	}
+
+	// Render all elements, then the overlay layer above them
 	if err := w.Element.Render(w.canvas); err != nil {
 		return err
 	}
+	if err := w.overlay.Render(w.canvas); err != nil {
+		return err
+	}
 
-	// Present to screen
-	return w.canvas.Present()
+	// Present the damaged region to screen
+	return w.canvas.Present(damage)
 }
 
-// PollEvents processes pending events
+// resize fits the window contents and overlay to a new window size
+func (w *Window) resize(width, height int) {
+	w.Element.SetSize(width, height)
+	w.overlay.SetSize(width, height)
+	w.Element.InvalidateLayout()
+	w.Invalidate()
+}
+
+// PollEvents returns pending renderer events followed by due timers
 func (w *Window) PollEvents() []Event {
-	return w.renderer.PollEvents()
+	return append(w.renderer.PollEvents(), w.dueTimers(time.Now())...)
+}
+
+// HandleEvent runs window-level events such as timers and routes the
+// rest to the element tree
+func (w *Window) HandleEvent(event Event) bool {
+	handled := w.routeEvent(event)
+	w.updateTextInputRect()
+	return handled
+}
+
+// routeEvent delivers an event to the window or the elements it concerns
+func (w *Window) routeEvent(event Event) bool {
+	switch ev := event.(type) {
+	case *TimerEvent:
+		if ev.Timer != nil && ev.Timer.window == w {
+			ev.Timer.fire(ev)
+			return true
+		}
+		return false
+	case *MouseDownEvent:
+		return w.handleMouseDown(ev)
+	case *MouseMoveEvent:
+		return w.handleMouseMove(ev)
+	case *MouseLeaveEvent:
+		if w.pointer.captured == nil {
+			w.updateHover(nil, ev.X, ev.Y) // The pointer left the window
+		}
+		return true
+	case *MouseUpEvent:
+		return w.handleMouseUp(ev)
+	case *ResizeEvent:
+		w.resize(ev.Width, ev.Height)
+		w.Element.HandleEvent(event)
+		return true
+	case *KeyPressEvent:
+		if w.drag != nil && ev.Key == KeyEscape {
+			w.cancelDrag(w.pointer.x, w.pointer.y)
+			return true
+		}
+		return w.focus.dispatch(event)
+	case *KeyReleaseEvent, *TextInputEvent,
+		*CompositionStartEvent, *CompositionUpdateEvent, *CompositionEndEvent:
+		return w.focus.dispatch(event)
+	}
+
+	if touch, ok := event.(interface{ touchEvent() *TouchEvent }); ok {
+		return w.handleTouch(event, touch.touchEvent())
+	}
+	if posEvent, ok := event.(PositionedEvent); ok && routesByPosition(event) {
+		return w.routePointer(posEvent)
+	}
+	return w.Element.HandleEvent(event)
 }
@@ -0,0 +1,96 @@
+package gui
+
+// CursorShape identifies a mouse cursor image
+type CursorShape int
+
+const (
+	// CursorDefault inherits the cursor of the parent element
+	CursorDefault CursorShape = iota
+	CursorArrow
+	CursorIBeam
+	CursorHand
+	CursorCrosshair
+	CursorWait
+	CursorMove
+	CursorNotAllowed
+	CursorResizeEW   // Horizontal resize
+	CursorResizeNS   // Vertical resize
+	CursorResizeNWSE // Diagonal resize, top-left to bottom-right
+	CursorResizeNESW // Diagonal resize, top-right to bottom-left
+)
+
+// SetCursor sets the cursor shown while the pointer is over the element
+func (e *Element) SetCursor(shape CursorShape) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.cursor = shape
+}
+
+// Cursor returns the element's cursor, CursorDefault meaning inherited
+func (e *Element) Cursor() CursorShape {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.cursor
+}
+
+// Hovered returns the element directly under the pointer, or nil
+func (w *Window) Hovered() GUIElement {
+	if len(w.hovered) == 0 {
+		return nil
+	}
+	return w.hovered[len(w.hovered)-1]
+}
+
+// updateHover replaces the hovered chain with path, sending MouseLeaveEvent
+// to elements the pointer left, innermost first, and MouseEnterEvent to
+// elements it entered, outermost first
+func (w *Window) updateHover(path []GUIElement, x, y int) {
+	common := 0
+	for common < len(path) && common < len(w.hovered) && path[common] == w.hovered[common] {
+		common++
+	}
+
+	left := w.hovered[common:]
+	w.hovered = path
+
+	for i := len(left) - 1; i >= 0; i-- {
+		dispatchTo(left[i], NewMouseLeaveEvent(x, y))
+	}
+	for _, el := range path[common:] {
+		dispatchTo(el, NewMouseEnterEvent(x, y))
+	}
+
+	w.updateCursor()
+}
+
+// updateCursor shows the cursor of the innermost hovered element that declares one
+func (w *Window) updateCursor() {
+	shape := w.Element.Cursor()
+	for i := len(w.hovered) - 1; i >= 0; i-- {
+		if base := baseOf(w.hovered[i]); base != nil {
+			if c := base.Cursor(); c != CursorDefault {
+				shape = c
+				break
+			}
+		}
+	}
+	if shape == CursorDefault {
+		shape = CursorArrow
+	}
+
+	if shape != w.cursor && w.renderer.SetCursor(shape) == nil {
+		w.cursor = shape
+	}
+}
+
+// dispatchTo delivers an event to a single element without capture or bubbling
+func dispatchTo(el GUIElement, event Event) bool {
+	if state := stateOf(event); state != nil {
+		state.target = el
+		defer func() {
+			state.currentTarget = nil
+			state.phase = PhaseNone
+		}()
+	}
+	return invokeAt(el, event, PhaseAtTarget, true) || invokeAt(el, event, PhaseAtTarget, false)
+}
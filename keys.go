@@ -0,0 +1,472 @@
+package gui
+
+import "strings"
+
+// Key identifies a logical key, after the keyboard layout has been applied
+type Key int
+
+const (
+	KeyUnknown Key = iota
+	KeyA
+	KeyB
+	KeyC
+	KeyD
+	KeyE
+	KeyF
+	KeyG
+	KeyH
+	KeyI
+	KeyJ
+	KeyK
+	KeyL
+	KeyM
+	KeyN
+	KeyO
+	KeyP
+	KeyQ
+	KeyR
+	KeyS
+	KeyT
+	KeyU
+	KeyV
+	KeyW
+	KeyX
+	KeyY
+	KeyZ
+	Key0
+	Key1
+	Key2
+	Key3
+	Key4
+	Key5
+	Key6
+	Key7
+	Key8
+	Key9
+	KeySpace
+	KeyEnter
+	KeyTab
+	KeyBackspace
+	KeyDelete
+	KeyEscape
+	KeyArrowUp
+	KeyArrowDown
+	KeyArrowLeft
+	KeyArrowRight
+	KeyHome
+	KeyEnd
+	KeyPageUp
+	KeyPageDown
+	KeyInsert
+	KeyF1
+	KeyF2
+	KeyF3
+	KeyF4
+	KeyF5
+	KeyF6
+	KeyF7
+	KeyF8
+	KeyF9
+	KeyF10
+	KeyF11
+	KeyF12
+	KeyF13
+	KeyF14
+	KeyF15
+	KeyF16
+	KeyF17
+	KeyF18
+	KeyF19
+	KeyF20
+	KeyF21
+	KeyF22
+	KeyF23
+	KeyF24
+	KeyMinus
+	KeyEqual
+	KeyBracketLeft
+	KeyBracketRight
+	KeyBackslash
+	KeySemicolon
+	KeyApostrophe
+	KeyGrave
+	KeyComma
+	KeyPeriod
+	KeySlash
+	KeyNumpad0
+	KeyNumpad1
+	KeyNumpad2
+	KeyNumpad3
+	KeyNumpad4
+	KeyNumpad5
+	KeyNumpad6
+	KeyNumpad7
+	KeyNumpad8
+	KeyNumpad9
+	KeyNumpadDecimal
+	KeyNumpadDivide
+	KeyNumpadMultiply
+	KeyNumpadSubtract
+	KeyNumpadAdd
+	KeyNumpadEnter
+	KeyNumpadEqual
+	KeyShiftLeft
+	KeyShiftRight
+	KeyControlLeft
+	KeyControlRight
+	KeyAltLeft
+	KeyAltRight
+	KeySuperLeft
+	KeySuperRight
+	KeyCapsLock
+	KeyNumLock
+	KeyScrollLock
+	KeyPrintScreen
+	KeyPause
+	KeyMenu
+)
+
+// keyNames holds the canonical name of every key, as used by String and ParseKey
+var keyNames = [...]string{
+	KeyUnknown:        "Unknown",
+	KeyA:              "A",
+	KeyB:              "B",
+	KeyC:              "C",
+	KeyD:              "D",
+	KeyE:              "E",
+	KeyF:              "F",
+	KeyG:              "G",
+	KeyH:              "H",
+	KeyI:              "I",
+	KeyJ:              "J",
+	KeyK:              "K",
+	KeyL:              "L",
+	KeyM:              "M",
+	KeyN:              "N",
+	KeyO:              "O",
+	KeyP:              "P",
+	KeyQ:              "Q",
+	KeyR:              "R",
+	KeyS:              "S",
+	KeyT:              "T",
+	KeyU:              "U",
+	KeyV:              "V",
+	KeyW:              "W",
+	KeyX:              "X",
+	KeyY:              "Y",
+	KeyZ:              "Z",
+	Key0:              "0",
+	Key1:              "1",
+	Key2:              "2",
+	Key3:              "3",
+	Key4:              "4",
+	Key5:              "5",
+	Key6:              "6",
+	Key7:              "7",
+	Key8:              "8",
+	Key9:              "9",
+	KeySpace:          "Space",
+	KeyEnter:          "Enter",
+	KeyTab:            "Tab",
+	KeyBackspace:      "Backspace",
+	KeyDelete:         "Delete",
+	KeyEscape:         "Escape",
+	KeyArrowUp:        "ArrowUp",
+	KeyArrowDown:      "ArrowDown",
+	KeyArrowLeft:      "ArrowLeft",
+	KeyArrowRight:     "ArrowRight",
+	KeyHome:           "Home",
+	KeyEnd:            "End",
+	KeyPageUp:         "PageUp",
+	KeyPageDown:       "PageDown",
+	KeyInsert:         "Insert",
+	KeyF1:             "F1",
+	KeyF2:             "F2",
+	KeyF3:             "F3",
+	KeyF4:             "F4",
+	KeyF5:             "F5",
+	KeyF6:             "F6",
+	KeyF7:             "F7",
+	KeyF8:             "F8",
+	KeyF9:             "F9",
+	KeyF10:            "F10",
+	KeyF11:            "F11",
+	KeyF12:            "F12",
+	KeyF13:            "F13",
+	KeyF14:            "F14",
+	KeyF15:            "F15",
+	KeyF16:            "F16",
+	KeyF17:            "F17",
+	KeyF18:            "F18",
+	KeyF19:            "F19",
+	KeyF20:            "F20",
+	KeyF21:            "F21",
+	KeyF22:            "F22",
+	KeyF23:            "F23",
+	KeyF24:            "F24",
+	KeyMinus:          "Minus",
+	KeyEqual:          "Equal",
+	KeyBracketLeft:    "BracketLeft",
+	KeyBracketRight:   "BracketRight",
+	KeyBackslash:      "Backslash",
+	KeySemicolon:      "Semicolon",
+	KeyApostrophe:     "Apostrophe",
+	KeyGrave:          "Grave",
+	KeyComma:          "Comma",
+	KeyPeriod:         "Period",
+	KeySlash:          "Slash",
+	KeyNumpad0:        "Numpad0",
+	KeyNumpad1:        "Numpad1",
+	KeyNumpad2:        "Numpad2",
+	KeyNumpad3:        "Numpad3",
+	KeyNumpad4:        "Numpad4",
+	KeyNumpad5:        "Numpad5",
+	KeyNumpad6:        "Numpad6",
+	KeyNumpad7:        "Numpad7",
+	KeyNumpad8:        "Numpad8",
+	KeyNumpad9:        "Numpad9",
+	KeyNumpadDecimal:  "NumpadDecimal",
+	KeyNumpadDivide:   "NumpadDivide",
+	KeyNumpadMultiply: "NumpadMultiply",
+	KeyNumpadSubtract: "NumpadSubtract",
+	KeyNumpadAdd:      "NumpadAdd",
+	KeyNumpadEnter:    "NumpadEnter",
+	KeyNumpadEqual:    "NumpadEqual",
+	KeyShiftLeft:      "ShiftLeft",
+	KeyShiftRight:     "ShiftRight",
+	KeyControlLeft:    "ControlLeft",
+	KeyControlRight:   "ControlRight",
+	KeyAltLeft:        "AltLeft",
+	KeyAltRight:       "AltRight",
+	KeySuperLeft:      "SuperLeft",
+	KeySuperRight:     "SuperRight",
+	KeyCapsLock:       "CapsLock",
+	KeyNumLock:        "NumLock",
+	KeyScrollLock:     "ScrollLock",
+	KeyPrintScreen:    "PrintScreen",
+	KeyPause:          "Pause",
+	KeyMenu:           "Menu",
+}
+
+// String returns the key's canonical name, such as "A", "F5" or "PageUp"
+func (k Key) String() string {
+	if k >= 0 && int(k) < len(keyNames) {
+		return keyNames[k]
+	}
+	return keyNames[KeyUnknown]
+}
+
+// ParseKey looks up a key by its canonical name, ignoring case
+func ParseKey(name string) (Key, bool) {
+	for k, n := range keyNames {
+		if strings.EqualFold(n, name) {
+			return Key(k), true
+		}
+	}
+	return KeyUnknown, false
+}
+
+// Modifier returns the modifier bit a key controls, or ModifierNone for
+// ordinary keys; renderers use it to track modifier state from key events
+func (k Key) Modifier() KeyModifiers {
+	switch k {
+	case KeyShiftLeft, KeyShiftRight:
+		return ModifierShift
+	case KeyControlLeft, KeyControlRight:
+		return ModifierCtrl
+	case KeyAltLeft, KeyAltRight:
+		return ModifierAlt
+	case KeySuperLeft, KeySuperRight:
+		return ModifierSuper
+	}
+	return ModifierNone
+}
+
+// KeyModifiers is a bitmask of held modifier keys and active locks
+type KeyModifiers int
+
+const (
+	ModifierShift KeyModifiers = 1 << iota
+	ModifierCtrl
+	ModifierAlt
+	ModifierSuper
+	ModifierCapsLock
+	ModifierNumLock
+)
+
+// ModifierNone means no modifier is held
+const ModifierNone KeyModifiers = 0
+
+// modifierNames lists modifier bits in the order String prints them
+var modifierNames = []struct {
+	modifier KeyModifiers
+	name     string
+}{
+	{ModifierCtrl, "Ctrl"},
+	{ModifierAlt, "Alt"},
+	{ModifierShift, "Shift"},
+	{ModifierSuper, "Super"},
+	{ModifierCapsLock, "CapsLock"},
+	{ModifierNumLock, "NumLock"},
+}
+
+// Has reports whether all bits of m are set
+func (k KeyModifiers) Has(m KeyModifiers) bool {
+	return k&m == m
+}
+
+// String returns the held modifiers joined with "+", such as "Ctrl+Shift"
+func (k KeyModifiers) String() string {
+	var names []string
+	for _, entry := range modifierNames {
+		if k.Has(entry.modifier) {
+			names = append(names, entry.name)
+		}
+	}
+	return strings.Join(names, "+")
+}
+
+// Scancode identifies a physical key position independent of the keyboard
+// layout, using USB HID keyboard usage IDs (usage page 0x07). Backends
+// translate their native codes, such as evdev or X11 keycodes, to it.
+type Scancode uint32
+
+// scancodeKeys maps scancodes to keys on a US QWERTY layout
+var scancodeKeys = map[Scancode]Key{
+	0x04: KeyA,
+	0x05: KeyB,
+	0x06: KeyC,
+	0x07: KeyD,
+	0x08: KeyE,
+	0x09: KeyF,
+	0x0A: KeyG,
+	0x0B: KeyH,
+	0x0C: KeyI,
+	0x0D: KeyJ,
+	0x0E: KeyK,
+	0x0F: KeyL,
+	0x10: KeyM,
+	0x11: KeyN,
+	0x12: KeyO,
+	0x13: KeyP,
+	0x14: KeyQ,
+	0x15: KeyR,
+	0x16: KeyS,
+	0x17: KeyT,
+	0x18: KeyU,
+	0x19: KeyV,
+	0x1A: KeyW,
+	0x1B: KeyX,
+	0x1C: KeyY,
+	0x1D: KeyZ,
+	0x27: Key0,
+	0x1E: Key1,
+	0x1F: Key2,
+	0x20: Key3,
+	0x21: Key4,
+	0x22: Key5,
+	0x23: Key6,
+	0x24: Key7,
+	0x25: Key8,
+	0x26: Key9,
+	0x2C: KeySpace,
+	0x28: KeyEnter,
+	0x2B: KeyTab,
+	0x2A: KeyBackspace,
+	0x4C: KeyDelete,
+	0x29: KeyEscape,
+	0x52: KeyArrowUp,
+	0x51: KeyArrowDown,
+	0x50: KeyArrowLeft,
+	0x4F: KeyArrowRight,
+	0x4A: KeyHome,
+	0x4D: KeyEnd,
+	0x4B: KeyPageUp,
+	0x4E: KeyPageDown,
+	0x49: KeyInsert,
+	0x3A: KeyF1,
+	0x3B: KeyF2,
+	0x3C: KeyF3,
+	0x3D: KeyF4,
+	0x3E: KeyF5,
+	0x3F: KeyF6,
+	0x40: KeyF7,
+	0x41: KeyF8,
+	0x42: KeyF9,
+	0x43: KeyF10,
+	0x44: KeyF11,
+	0x45: KeyF12,
+	0x68: KeyF13,
+	0x69: KeyF14,
+	0x6A: KeyF15,
+	0x6B: KeyF16,
+	0x6C: KeyF17,
+	0x6D: KeyF18,
+	0x6E: KeyF19,
+	0x6F: KeyF20,
+	0x70: KeyF21,
+	0x71: KeyF22,
+	0x72: KeyF23,
+	0x73: KeyF24,
+	0x2D: KeyMinus,
+	0x2E: KeyEqual,
+	0x2F: KeyBracketLeft,
+	0x30: KeyBracketRight,
+	0x31: KeyBackslash,
+	0x33: KeySemicolon,
+	0x34: KeyApostrophe,
+	0x35: KeyGrave,
+	0x36: KeyComma,
+	0x37: KeyPeriod,
+	0x38: KeySlash,
+	0x62: KeyNumpad0,
+	0x59: KeyNumpad1,
+	0x5A: KeyNumpad2,
+	0x5B: KeyNumpad3,
+	0x5C: KeyNumpad4,
+	0x5D: KeyNumpad5,
+	0x5E: KeyNumpad6,
+	0x5F: KeyNumpad7,
+	0x60: KeyNumpad8,
+	0x61: KeyNumpad9,
+	0x63: KeyNumpadDecimal,
+	0x54: KeyNumpadDivide,
+	0x55: KeyNumpadMultiply,
+	0x56: KeyNumpadSubtract,
+	0x57: KeyNumpadAdd,
+	0x58: KeyNumpadEnter,
+	0x67: KeyNumpadEqual,
+	0xE1: KeyShiftLeft,
+	0xE5: KeyShiftRight,
+	0xE0: KeyControlLeft,
+	0xE4: KeyControlRight,
+	0xE2: KeyAltLeft,
+	0xE6: KeyAltRight,
+	0xE3: KeySuperLeft,
+	0xE7: KeySuperRight,
+	0x39: KeyCapsLock,
+	0x53: KeyNumLock,
+	0x47: KeyScrollLock,
+	0x46: KeyPrintScreen,
+	0x48: KeyPause,
+	0x65: KeyMenu,
+}
+
+// DefaultKeyForScancode returns the logical key at a physical position on
+// a US QWERTY layout, for backends without a layout of their own
+func DefaultKeyForScancode(code Scancode) Key {
+	if key, ok := scancodeKeys[code]; ok {
+		return key
+	}
+	return KeyUnknown
+}
+
+// DefaultScancodeForKey returns the physical position of a key on a US QWERTY layout
+func DefaultScancodeForKey(key Key) (Scancode, bool) {
+	for code, k := range scancodeKeys {
+		if k == key {
+			return code, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,365 @@
+package gui
+
+import (
+	"image"
+	"math"
+)
+
+// Unbounded is the maximum of a constraint that places no limit
+const Unbounded = math.MaxInt32
+
+// Size is a width and height in pixels
+type Size struct {
+	Width, Height int
+}
+
+// Insets are distances from the four edges of a rectangle, used for
+// margins and padding
+type Insets struct {
+	Top, Right, Bottom, Left int
+}
+
+// UniformInsets returns insets of the same distance on every edge
+func UniformInsets(n int) Insets {
+	return Insets{Top: n, Right: n, Bottom: n, Left: n}
+}
+
+// Horizontal returns the sum of the left and right insets
+func (i Insets) Horizontal() int {
+	return i.Left + i.Right
+}
+
+// Vertical returns the sum of the top and bottom insets
+func (i Insets) Vertical() int {
+	return i.Top + i.Bottom
+}
+
+// Constraints bound the size an element may take during measuring
+type Constraints struct {
+	MinWidth, MinHeight int
+	MaxWidth, MaxHeight int
+}
+
+// TightConstraints allow exactly one size
+func TightConstraints(width, height int) Constraints {
+	return Constraints{MinWidth: width, MinHeight: height, MaxWidth: width, MaxHeight: height}
+}
+
+// LooseConstraints allow any size up to width and height
+func LooseConstraints(width, height int) Constraints {
+	return Constraints{MaxWidth: width, MaxHeight: height}
+}
+
+// UnboundedConstraints allow any size at all
+func UnboundedConstraints() Constraints {
+	return Constraints{MaxWidth: Unbounded, MaxHeight: Unbounded}
+}
+
+// Constrain clamps a size into the constraints
+func (c Constraints) Constrain(s Size) Size {
+	return Size{Width: clampInt(s.Width, c.MinWidth, c.MaxWidth), Height: clampInt(s.Height, c.MinHeight, c.MaxHeight)}
+}
+
+// Deflate shrinks the constraints by insets, for measuring what lies inside them
+func (c Constraints) Deflate(insets Insets) Constraints {
+	return Constraints{
+		MinWidth:  maxInt(c.MinWidth-insets.Horizontal(), 0),
+		MinHeight: maxInt(c.MinHeight-insets.Vertical(), 0),
+		MaxWidth:  deflateMax(c.MaxWidth, insets.Horizontal()),
+		MaxHeight: deflateMax(c.MaxHeight, insets.Vertical()),
+	}
+}
+
+// narrow applies an element's own size limits within the constraints; the
+// constraints win where the two disagree, and a zero maximum means none
+func (c Constraints) narrow(min, max Size) Constraints {
+	n := c
+	n.MinWidth = clampInt(min.Width, c.MinWidth, c.MaxWidth)
+	n.MinHeight = clampInt(min.Height, c.MinHeight, c.MaxHeight)
+	if max.Width > 0 {
+		n.MaxWidth = clampInt(max.Width, n.MinWidth, c.MaxWidth)
+	}
+	if max.Height > 0 {
+		n.MaxHeight = clampInt(max.Height, n.MinHeight, c.MaxHeight)
+	}
+	return n
+}
+
+// deflateMax subtracts from a maximum, leaving Unbounded alone
+func deflateMax(max, n int) int {
+	if max >= Unbounded {
+		return Unbounded
+	}
+	return maxInt(max-n, 0)
+}
+
+func clampInt(v, lo, hi int) int {
+	if v > hi {
+		v = hi
+	}
+	if v < lo {
+		v = lo
+	}
+	return v
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// Layout positions an element's children in two passes: Measure reports
+// the size the children want and Arrange gives them their bounds
+type Layout interface {
+	// Measure returns the size needed for the children within the
+	// constraints, excluding the container's padding
+	Measure(children []GUIElement, constraints Constraints) Size
+
+	// Arrange positions the children inside area, which is the container's
+	// content area in its own coordinates
+	Arrange(children []GUIElement, area image.Rectangle)
+}
+
+// ChildRemovedLayout is implemented by layouts that keep settings per
+// child, so they can drop them when the child leaves the container
+type ChildRemovedLayout interface {
+	Layout
+
+	// ChildRemoved is called after child is removed or moved to another parent
+	ChildRemoved(child GUIElement)
+}
+
+// ContentMeasurer is implemented by elements with a natural size of their
+// own, such as text, used when they have no layout or preferred size
+type ContentMeasurer interface {
+	// MeasureContent returns the size of the content, excluding padding
+	MeasureContent(constraints Constraints) Size
+}
+
+// SetLayout sets the layout arranging the element's children; nil leaves
+// them where they are placed by hand
+func (e *Element) SetLayout(layout Layout) {
+	e.mu.Lock()
+	e.layout = layout
+	e.mu.Unlock()
+	e.InvalidateLayout()
+}
+
+// Layout returns the element's layout, or nil
+func (e *Element) Layout() Layout {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.layout
+}
+
+// SetMinSize sets the smallest size layouts may give the element
+func (e *Element) SetMinSize(width, height int) {
+	e.mu.Lock()
+	e.minSize = Size{Width: width, Height: height}
+	e.mu.Unlock()
+	e.InvalidateLayout()
+}
+
+// MinSize returns the element's minimum size
+func (e *Element) MinSize() (width, height int) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.minSize.Width, e.minSize.Height
+}
+
+// SetMaxSize sets the largest size layouts may give the element; zero
+// leaves a dimension unlimited
+func (e *Element) SetMaxSize(width, height int) {
+	e.mu.Lock()
+	e.maxSize = Size{Width: width, Height: height}
+	e.mu.Unlock()
+	e.InvalidateLayout()
+}
+
+// MaxSize returns the element's maximum size, zero meaning unlimited
+func (e *Element) MaxSize() (width, height int) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.maxSize.Width, e.maxSize.Height
+}
+
+// SetPreferredSize sets the size the element asks layouts for; zero
+// measures a dimension from the content instead
+func (e *Element) SetPreferredSize(width, height int) {
+	e.mu.Lock()
+	e.preferredSize = Size{Width: width, Height: height}
+	e.mu.Unlock()
+	e.InvalidateLayout()
+}
+
+// PreferredSize returns the element's preferred size, zero meaning measured
+func (e *Element) PreferredSize() (width, height int) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.preferredSize.Width, e.preferredSize.Height
+}
+
+// SetMargin sets the space layouts keep around the element
+func (e *Element) SetMargin(margin Insets) {
+	e.mu.Lock()
+	e.margin = margin
+	e.mu.Unlock()
+	e.InvalidateLayout()
+}
+
+// Margin returns the space kept around the element
+func (e *Element) Margin() Insets {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.margin
+}
+
+// SetPadding sets the space between the element's edges and its children
+// or content
+func (e *Element) SetPadding(padding Insets) {
+	e.mu.Lock()
+	e.padding = padding
+	e.mu.Unlock()
+	e.InvalidateLayout()
+}
+
+// Padding returns the space between the element's edges and its content
+func (e *Element) Padding() Insets {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.padding
+}
+
+// InvalidateLayout marks the element and its ancestors as needing a layout
+// pass, which the window runs before its next repaint
+func (e *Element) InvalidateLayout() {
+	for el := e; el != nil; el = el.parentElement() {
+		el.mu.Lock()
+		el.needsLayout = true
+		el.mu.Unlock()
+	}
+}
+
+// NeedsLayout reports whether a layout pass is pending for the element
+func (e *Element) NeedsLayout() bool {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.needsLayout
+}
+
+// Measure returns the size the element wants within the constraints: its
+// preferred size where set, otherwise the size its layout or content
+// needs plus padding, otherwise the size last given to SetSize, kept within
+// its minimum and maximum
+func (e *Element) Measure(constraints Constraints) Size {
+	e.mu.RLock()
+	layout, padding := e.layout, e.padding
+	preferred := e.preferredSize
+	c := constraints.narrow(e.minSize, e.maxSize)
+	natural := e.naturalSize
+	children := e.snapshotChildren()
+	e.mu.RUnlock()
+
+	// A preferred dimension is measured as if it were fixed, so wrapped
+	// content sees the width it will get
+	if preferred.Width > 0 {
+		c.MinWidth = clampInt(preferred.Width, c.MinWidth, c.MaxWidth)
+		c.MaxWidth = c.MinWidth
+	}
+	if preferred.Height > 0 {
+		c.MinHeight = clampInt(preferred.Height, c.MinHeight, c.MaxHeight)
+		c.MaxHeight = c.MinHeight
+	}
+
+	size := natural
+	if layout != nil {
+		size = layout.Measure(visibleChildren(children), c.Deflate(padding))
+		size.Width += padding.Horizontal()
+		size.Height += padding.Vertical()
+	} else if content, ok := e.self().(ContentMeasurer); ok {
+		size = content.MeasureContent(c.Deflate(padding))
+		size.Width += padding.Horizontal()
+		size.Height += padding.Vertical()
+	}
+	return c.Constrain(size)
+}
+
+// MeasureElement measures any GUIElement; elements without a base keep
+// their current size
+func MeasureElement(el GUIElement, constraints Constraints) Size {
+	if base := baseOf(el); base != nil {
+		return base.Measure(constraints)
+	}
+	_, _, width, height := el.GetBounds()
+	return constraints.Constrain(Size{Width: width, Height: height})
+}
+
+// ArrangeElement gives a GUIElement its bounds in its parent's coordinates;
+// its own children are arranged later in the same layout pass
+func ArrangeElement(el GUIElement, bounds image.Rectangle) {
+	el.SetPosition(bounds.Min.X, bounds.Min.Y)
+	if base := baseOf(el); base != nil {
+		base.resize(bounds.Dx(), bounds.Dy()) // Keeps the natural size for measuring
+		return
+	}
+	if sizer, ok := el.(interface{ SetSize(width, height int) }); ok {
+		sizer.SetSize(bounds.Dx(), bounds.Dy())
+	}
+}
+
+// MarginOf returns a GUIElement's margin, zero for elements without a base
+func MarginOf(el GUIElement) Insets {
+	if base := baseOf(el); base != nil {
+		return base.Margin()
+	}
+	return Insets{}
+}
+
+// visibleChildren drops hidden children, which take no space in layouts
+func visibleChildren(children []GUIElement) []GUIElement {
+	visible := children[:0:0]
+	for _, child := range children {
+		if child.IsVisible() {
+			visible = append(visible, child)
+		}
+	}
+	return visible
+}
+
+// UpdateLayout runs a layout pass over the element's subtree, arranging
+// the children of every element whose layout was invalidated
+func (e *Element) UpdateLayout() {
+	e.updateLayout(false)
+}
+
+// updateLayout arranges the element's children if it has a layout and it
+// was invalidated or forced, then descends; children of an arranged element
+// are forced since their sizes may have changed
+func (e *Element) updateLayout(force bool) {
+	e.mu.Lock()
+	if !force && !e.needsLayout {
+		// Invalidation marks every ancestor, so nothing below needs work
+		e.mu.Unlock()
+		return
+	}
+	layout := e.layout
+	arrange := layout != nil
+	e.needsLayout = false
+	area := image.Rect(e.padding.Left, e.padding.Top, e.width-e.padding.Right, e.height-e.padding.Bottom)
+	children := e.snapshotChildren()
+	e.mu.Unlock()
+
+	if arrange {
+		if area.Dx() < 0 || area.Dy() < 0 {
+			area = image.Rectangle{Min: area.Min, Max: area.Min}
+		}
+		layout.Arrange(visibleChildren(children), area)
+	}
+	for _, child := range children {
+		if base := baseOf(child); base != nil {
+			base.updateLayout(arrange)
+		}
+	}
+}
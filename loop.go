@@ -0,0 +1,259 @@
+package gui
+
+import (
+	"context"
+	"time"
+)
+
+// DefaultMaxFPS is the frame rate cap applied to new windows
+const DefaultMaxFPS = 60
+
+// Animation advances once per frame while Step returns true
+type Animation interface {
+	Step(now time.Time) bool
+}
+
+// FrameFunc runs before each frame while it returns true
+type FrameFunc func(now time.Time) bool
+
+// Step adapts functions to the Animation interface
+func (f FrameFunc) Step(now time.Time) bool {
+	return f(now)
+}
+
+// FrameStats describes the timing of a rendered frame
+type FrameStats struct {
+	Frame    uint64        // Sequence number of the frame
+	Start    time.Time     // When the frame began
+	Interval time.Duration // Time since the previous frame began
+	Duration time.Duration // Time spent running frame callbacks and rendering
+}
+
+// SetMaxFPS caps the frame rate of Run; zero or less removes the cap
+func (w *Window) SetMaxFPS(fps int) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.maxFPS = fps
+}
+
+// SetOnFrame sets a callback that receives the timing of every rendered frame
+func (w *Window) SetOnFrame(callback func(stats FrameStats)) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.onFrame = callback
+}
+
+// LastFrame returns the timing of the most recently rendered frame
+func (w *Window) LastFrame() FrameStats {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.lastFrame
+}
+
+// RequestFrames keeps Run producing frames while fn returns true,
+// which is how animations drive the window
+func (w *Window) RequestFrames(fn FrameFunc) {
+	w.mu.Lock()
+	w.frameFuncs = append(w.frameFuncs, fn)
+	w.mu.Unlock()
+
+	// Wake an idle Run so the animation starts on the next frame
+	w.renderer.Wake()
+}
+
+// IsAnimating reports whether any frame callbacks or element animations are active
+func (w *Window) IsAnimating() bool {
+	w.mu.RLock()
+	active := len(w.frameFuncs) > 0
+	w.mu.RUnlock()
+	return active || w.Element.IsAnimating()
+}
+
+// Animate attaches an animation to the element; the window steps it once
+// per frame for as long as the element is part of its tree
+func (e *Element) Animate(animation Animation) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.animations = append(e.animations, animation)
+}
+
+// IsAnimating reports whether the element or any descendant has active animations
+func (e *Element) IsAnimating() bool {
+	e.mu.RLock()
+	active := len(e.animations) > 0
+	children := e.snapshotChildren()
+	e.mu.RUnlock()
+
+	if active {
+		return true
+	}
+	for _, child := range children {
+		if base := baseOf(child); base != nil && base.IsAnimating() {
+			return true
+		}
+	}
+	return false
+}
+
+// stepAnimations advances the animations of the element and its
+// descendants, dropping those that finished
+func (e *Element) stepAnimations(now time.Time) {
+	e.mu.Lock()
+	animations := e.animations
+	e.animations = nil
+	children := e.snapshotChildren()
+	e.mu.Unlock()
+
+	var keep []Animation
+	for _, animation := range animations {
+		if animation.Step(now) {
+			keep = append(keep, animation)
+		}
+	}
+
+	// Animations started while stepping are kept after the survivors
+	e.mu.Lock()
+	e.animations = append(keep, e.animations...)
+	e.mu.Unlock()
+
+	for _, child := range children {
+		if base := baseOf(child); base != nil {
+			base.stepAnimations(now)
+		}
+	}
+}
+
+// Run shows the window and processes events until the window is closed
+// or ctx is cancelled. It blocks while idle and only wakes when events
+// arrive, a timer fires, something was invalidated or an animation is
+// active, rendering at most at the configured frame rate.
+func (w *Window) Run(ctx context.Context) error {
+	if !w.IsRunning() {
+		if err := w.Show(); err != nil {
+			return err
+		}
+	}
+
+	// Interrupt a blocking wait as soon as the context ends
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			w.renderer.Wake()
+		case <-done:
+		}
+	}()
+
+	for w.IsRunning() {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		events := w.renderer.WaitEvents(w.nextWait(time.Now()))
+		events = append(events, w.dueTimers(time.Now())...)
+		for _, event := range events {
+			w.HandleEvent(event)
+		}
+		w.runPosted()
+
+		if err := w.frame(time.Now()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// frameInterval returns the minimum time between frames
+func (w *Window) frameInterval() time.Duration {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	if w.maxFPS <= 0 {
+		return 0
+	}
+	return time.Second / time.Duration(w.maxFPS)
+}
+
+// nextWait returns how long Run may block waiting for events before a
+// frame or timer is due; a negative duration means until the next event
+func (w *Window) nextWait(now time.Time) time.Duration {
+	if w.hasPosted() {
+		return 0
+	}
+
+	wait, scheduled := time.Duration(0), false
+	if w.IsAnimating() || w.IsDirty() {
+		wait, scheduled = w.LastFrame().Start.Add(w.frameInterval()).Sub(now), true
+	}
+	if due, ok := w.nextTimer(); ok {
+		if untilTimer := due.Sub(now); !scheduled || untilTimer < wait {
+			wait, scheduled = untilTimer, true
+		}
+	}
+
+	if !scheduled {
+		return -1
+	}
+	if wait < 0 {
+		return 0
+	}
+	return wait
+}
+
+// frame repaints the window if a frame is due
+func (w *Window) frame(now time.Time) error {
+	last := w.LastFrame()
+	if !last.Start.IsZero() && now.Sub(last.Start) < w.frameInterval() {
+		return nil // Frame rate cap; nextWait schedules the frame
+	}
+	if !w.IsAnimating() && !w.IsDirty() {
+		return nil
+	}
+
+	if err := w.Update(); err != nil {
+		return err
+	}
+
+	stats := FrameStats{
+		Frame:    last.Frame + 1,
+		Start:    now,
+		Duration: time.Since(now),
+	}
+	if !last.Start.IsZero() {
+		stats.Interval = now.Sub(last.Start)
+	}
+
+	w.mu.Lock()
+	w.lastFrame = stats
+	onFrame := w.onFrame
+	w.mu.Unlock()
+
+	if onFrame != nil {
+		onFrame(stats)
+	}
+	return nil
+}
+
+// runFrameFuncs invokes frame callbacks and element animations,
+// dropping those that finished
+func (w *Window) runFrameFuncs(now time.Time) {
+	w.mu.Lock()
+	funcs := w.frameFuncs
+	w.frameFuncs = nil
+	w.mu.Unlock()
+
+	var keep []FrameFunc
+	for _, fn := range funcs {
+		if fn(now) {
+			keep = append(keep, fn)
+		}
+	}
+
+	// Callbacks requested while running are kept after the survivors
+	w.mu.Lock()
+	w.frameFuncs = append(keep, w.frameFuncs...)
+	w.mu.Unlock()
+
+	w.Element.stepAnimations(now)
+}
@@ -0,0 +1,237 @@
+package gui
+
+import "time"
+
+// Double-click detection thresholds
+const (
+	DoubleClickInterval = 500 * time.Millisecond
+	DoubleClickDistance = 4
+)
+
+// pointerState tracks a held mouse button so the window can synthesize
+// clicks and drags; it is only touched on the UI loop
+type pointerState struct {
+	pressed        bool
+	button         MouseButton
+	target         GUIElement // Element under the pointer at MouseDown, nil for the window itself
+	startX, startY int
+	lastX, lastY   int
+	x, y           int        // Last known pointer position
+	captured       GUIElement // Element receiving all pointer events until release
+	consumed       bool       // A cancelled drag used up the press; no drag or click follows
+
+	lastClick       time.Time
+	lastClickTarget GUIElement
+	lastClickButton MouseButton
+	lastClickX      int
+	lastClickY      int
+}
+
+// handleMouseDown records the press target and routes the event by position
+func (w *Window) handleMouseDown(event *MouseDownEvent) bool {
+	w.pointer.x, w.pointer.y = event.X, event.Y
+	if !w.pointer.pressed {
+		w.pointer.pressed = true
+		w.pointer.consumed = false
+		w.pointer.button = event.Button
+		w.pointer.target = w.Element.elementAt(event.X, event.Y)
+		w.pointer.startX, w.pointer.startY = event.X, event.Y
+		w.pointer.lastX, w.pointer.lastY = event.X, event.Y
+	}
+
+	// Pressing focuses the innermost focusable element under the pointer
+	if w.pointer.captured == nil && w.ContainsPoint(event.X, event.Y) {
+		w.focus.focusAt(w.Element.pathAt(event.X, event.Y))
+	}
+
+	return w.routePointer(event)
+}
+
+// handleMouseMove updates hover state, dispatches the move to the element
+// under the pointer and turns it into a drag for the press target while a
+// button is held
+func (w *Window) handleMouseMove(event *MouseMoveEvent) bool {
+	w.pointer.x, w.pointer.y = event.X, event.Y
+
+	// A drag in progress takes over all pointer movement
+	if w.drag != nil {
+		w.dragMove(event.X, event.Y)
+		return true
+	}
+	if w.pointer.pressed && w.maybeStartDrag(event.X, event.Y) {
+		return true
+	}
+
+	// Hover is frozen while an element holds the pointer
+	if w.pointer.captured == nil {
+		w.updateHover(w.hoverPathAt(event.X, event.Y), event.X, event.Y)
+	}
+
+	handled := w.routePointer(event)
+
+	if w.pointer.pressed && !w.pointer.consumed {
+		drag := NewMouseDragEvent(event.X, event.Y, w.pointer.button,
+			w.pointer.startX, w.pointer.startY,
+			event.X-w.pointer.lastX, event.Y-w.pointer.lastY)
+		w.pointer.lastX, w.pointer.lastY = event.X, event.Y
+		target := w.pointer.target
+		if w.pointer.captured != nil {
+			target = w.pointer.captured
+		}
+		if w.deliverTo(target, drag) {
+			handled = true
+		}
+	}
+
+	return handled
+}
+
+// handleMouseUp delivers the release to the press target and synthesizes
+// a click, and possibly a double click, when it lands on the same element
+func (w *Window) handleMouseUp(event *MouseUpEvent) bool {
+	w.pointer.x, w.pointer.y = event.X, event.Y
+	if !w.pointer.pressed || event.Button != w.pointer.button {
+		handled := w.routePointer(event)
+		if event.Button == w.pointer.button {
+			w.ReleasePointer() // Another button's release leaves a capture alone
+		}
+		return handled
+	}
+
+	target := w.pointer.target
+	w.pointer.pressed = false
+	w.pointer.target = nil
+
+	if w.drag != nil || w.pointer.consumed {
+		if w.drag != nil {
+			w.finishDrop(event.X, event.Y)
+		}
+		w.pointer.consumed = false
+
+		// The press target still sees the release, but no click follows
+		event.PreventDefault()
+		w.deliverTo(target, event)
+		return true
+	}
+
+	var handled bool
+	if captured := w.pointer.captured; captured != nil {
+		handled = w.deliverTo(captured, event)
+		w.ReleasePointer()
+	} else {
+		handled = w.deliverTo(target, event)
+	}
+
+	// Handlers can veto the click by preventing the release's default
+	if event.IsDefaultPrevented() || w.Element.elementAt(event.X, event.Y) != target {
+		return handled
+	}
+
+	click := NewClickEvent(event.X, event.Y, event.Button)
+	click.Modifiers = event.Modifiers
+	if w.dispatchPointer(click) {
+		handled = true
+	}
+
+	if w.isDoubleClick(target, event) {
+		w.pointer.lastClick = time.Time{} // A third click starts over
+		doubleClick := NewDoubleClickEvent(event.X, event.Y, event.Button)
+		doubleClick.Modifiers = event.Modifiers
+		if w.dispatchPointer(doubleClick) {
+			handled = true
+		}
+	} else {
+		w.pointer.lastClick = event.Timestamp()
+		w.pointer.lastClickTarget = target
+		w.pointer.lastClickButton = event.Button
+		w.pointer.lastClickX, w.pointer.lastClickY = event.X, event.Y
+	}
+
+	return handled
+}
+
+// isDoubleClick reports whether a click follows the previous one closely
+// enough in time and space on the same element
+func (w *Window) isDoubleClick(target GUIElement, event *MouseUpEvent) bool {
+	p := &w.pointer
+	if p.lastClick.IsZero() || p.lastClickTarget != target || p.lastClickButton != event.Button {
+		return false
+	}
+	if event.Timestamp().Sub(p.lastClick) > DoubleClickInterval {
+		return false
+	}
+	dx, dy := event.X-p.lastClickX, event.Y-p.lastClickY
+	return dx*dx+dy*dy <= DoubleClickDistance*DoubleClickDistance
+}
+
+// CapturePointer sends all pointer events to element, wherever the pointer
+// is, until ReleasePointer is called or the mouse button is released. Hover
+// tracking is suspended while the capture is held.
+func (w *Window) CapturePointer(element GUIElement) {
+	if element == nil {
+		w.ReleasePointer()
+		return
+	}
+	w.pointer.captured = element
+}
+
+// ReleasePointer ends a pointer capture and restores hover tracking
+func (w *Window) ReleasePointer() {
+	if w.pointer.captured == nil {
+		return
+	}
+	w.pointer.captured = nil
+	w.updateHover(w.hoverPathAt(w.pointer.x, w.pointer.y), w.pointer.x, w.pointer.y)
+}
+
+// PointerCapture returns the element holding the pointer capture, or nil
+func (w *Window) PointerCapture() GUIElement {
+	return w.pointer.captured
+}
+
+// hoverPathAt returns the elements under a point, or nil outside the window
+func (w *Window) hoverPathAt(x, y int) []GUIElement {
+	if !w.ContainsPoint(x, y) {
+		return nil
+	}
+	return w.Element.pathAt(x, y)
+}
+
+// routePointer delivers a pointer event to the capturing element if there
+// is one, and otherwise to the element under the pointer
+func (w *Window) routePointer(event PositionedEvent) bool {
+	captured := w.pointer.captured
+	if captured == nil {
+		return w.dispatchPointer(event)
+	}
+	if _, ok := w.Element.pathTo(captured); !ok {
+		w.ReleasePointer() // The capturing element left the tree
+		return w.dispatchPointer(event)
+	}
+	return w.deliverTo(captured, event)
+}
+
+// dispatchPointer dispatches a pointer event to the deepest element under
+// the pointer, through capture, target and bubble phases
+func (w *Window) dispatchPointer(event PositionedEvent) bool {
+	x, y := event.Position()
+	if !w.ContainsPoint(x, y) {
+		return false
+	}
+	return dispatchAlong(append([]GUIElement{w}, w.Element.pathAt(x, y)...), event)
+}
+
+// deliverTo dispatches an event to a given target regardless of the pointer
+// position, still passing through its ancestors; a nil target means the
+// window itself
+func (w *Window) deliverTo(target GUIElement, event Event) bool {
+	path := []GUIElement{w}
+	if target != nil {
+		rest, ok := w.Element.pathTo(target)
+		if !ok {
+			return false // The target left the tree while the button was held
+		}
+		path = append(path, rest...)
+	}
+	return dispatchAlong(path, event)
+}
@@ -0,0 +1,186 @@
+package gui
+
+import (
+	"image"
+	"math"
+)
+
+// AddCaptureHandler registers a handler that runs while an event travels
+// from the root towards its target, before the target's own handlers
+func (e *Element) AddCaptureHandler(eventType EventType, handler EventHandler) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.captureHandlers[eventType] = append(e.captureHandlers[eventType], handler)
+}
+
+// HitClipper is implemented by elements that show their children only in
+// part of their area, such as a scrolling viewport, so points outside that
+// part hit the element rather than the children hidden there
+type HitClipper interface {
+	// HitClip returns the area, in the element's own coordinates, where its
+	// children can be hit
+	HitClip() image.Rectangle
+}
+
+// pathAt returns the visible descendants containing a point relative to
+// the element's top-left corner, from the outermost child down to the
+// deepest element
+func (e *Element) pathAt(x, y int) []GUIElement {
+	e.mu.RLock()
+	children := e.snapshotChildren()
+	e.mu.RUnlock()
+
+	for i := len(children) - 1; i >= 0; i-- {
+		child := children[i]
+		if !child.IsVisible() {
+			continue
+		}
+
+		base := baseOf(child)
+		if base == nil {
+			if image.Pt(x, y).In(boundsOf(child)) {
+				return []GUIElement{child}
+			}
+			continue
+		}
+
+		// Move the point into the child's own coordinates
+		cx, cy := base.toParent().Invert().Apply(float64(x), float64(y))
+		lx, ly := int(math.Floor(cx)), int(math.Floor(cy))
+		if !base.containsLocal(lx, ly) {
+			continue
+		}
+		if clipper, ok := child.(HitClipper); ok && !image.Pt(lx, ly).In(clipper.HitClip()) {
+			return []GUIElement{child}
+		}
+		return append([]GUIElement{child}, base.pathAt(lx, ly)...)
+	}
+	return nil
+}
+
+// elementAt returns the deepest visible descendant containing the point,
+// or nil when no child does
+func (e *Element) elementAt(x, y int) GUIElement {
+	path := e.pathAt(x, y)
+	if len(path) == 0 {
+		return nil
+	}
+	return path[len(path)-1]
+}
+
+// pathTo returns the descendants leading from the element down to target,
+// or false if target is not in the subtree
+func (e *Element) pathTo(target GUIElement) ([]GUIElement, bool) {
+	e.mu.RLock()
+	children := e.snapshotChildren()
+	e.mu.RUnlock()
+
+	for _, child := range children {
+		if child == target {
+			return []GUIElement{child}, true
+		}
+		if base := baseOf(child); base != nil {
+			if rest, ok := base.pathTo(target); ok {
+				return append([]GUIElement{child}, rest...), true
+			}
+		}
+	}
+	return nil, false
+}
+
+// dispatchAlong delivers an event to the last element of path in three
+// phases: capture handlers from the root down, the target's handlers,
+// then regular handlers bubbling back up. A handler returning true or
+// calling StopPropagation ends the dispatch.
+func dispatchAlong(path []GUIElement, event Event) bool {
+	if len(path) == 0 {
+		return false
+	}
+
+	state := stateOf(event)
+	target := path[len(path)-1]
+	if state != nil {
+		state.target = target
+		state.stopped = false
+		defer func() {
+			state.currentTarget = nil
+			state.phase = PhaseNone
+		}()
+	}
+
+	stopped := func() bool {
+		return state != nil && state.stopped
+	}
+
+	// Capture phase
+	for _, el := range path[:len(path)-1] {
+		if invokeAt(el, event, PhaseCapturing, true) {
+			return true
+		}
+		if stopped() {
+			return false
+		}
+	}
+
+	// Target phase runs capture handlers first, as the DOM does
+	if invokeAt(target, event, PhaseAtTarget, true) || invokeAt(target, event, PhaseAtTarget, false) {
+		return true
+	}
+	if stopped() {
+		return false
+	}
+
+	// Bubble phase
+	for i := len(path) - 2; i >= 0; i-- {
+		if invokeAt(path[i], event, PhaseBubbling, false) {
+			return true
+		}
+		if stopped() {
+			return false
+		}
+	}
+
+	return false
+}
+
+// invokeAt runs an element's capture or regular handlers for an event,
+// translating pointer coordinates into the element's local space
+func invokeAt(el GUIElement, event Event, phase EventPhase, capture bool) bool {
+	if state := stateOf(event); state != nil {
+		state.currentTarget = el
+		state.phase = phase
+	}
+	if pointer, ok := event.(interface {
+		Position() (x, y int)
+		setLocal(x, y int)
+	}); ok {
+		x, y := pointer.Position()
+		if base := baseOf(el); base != nil {
+			pointer.setLocal(base.WindowToLocal(x, y))
+		} else {
+			elX, elY, _, _ := el.GetBounds()
+			pointer.setLocal(x-elX, y-elY)
+		}
+	}
+
+	base := baseOf(el)
+	if base == nil {
+		// Elements without a base only take part as targets
+		if !capture && phase == PhaseAtTarget {
+			return el.HandleEvent(event)
+		}
+		return false
+	}
+	if capture {
+		return base.handleCapture(event)
+	}
+	return base.handleOwn(event)
+}
+
+// stateOf returns the propagation state of events embedding BaseEvent
+func stateOf(event Event) *BaseEvent {
+	if b, ok := event.(interface{ baseEvent() *BaseEvent }); ok {
+		return b.baseEvent()
+	}
+	return nil
+}
@@ -1,6 +1,10 @@
 package gui
 
-import "fmt"
+import (
+	"fmt"
+	"image"
+	"time"
+)
 
 // Renderer provides platform-specific window and rendering operations
 type Renderer interface {
@@ -14,6 +18,24 @@ type Renderer interface {
 	// Event handling
 	PollEvents() []Event
 
+	// WaitEvents blocks until events arrive, Wake is called or the timeout
+	// elapses; a negative timeout waits indefinitely
+	WaitEvents(timeout time.Duration) []Event
+
+	// Wake interrupts a pending WaitEvents; it is safe to call from any goroutine
+	Wake()
+
+	// Pointer
+	SetCursor(shape CursorShape) error
+
+	// SetTextInputRect tells the input method where the caret of the focused
+	// text field is, in window coordinates, so it can place its candidate
+	// window; an empty rectangle means no text field has focus
+	SetTextInputRect(rect image.Rectangle) error
+
+	// Clipboard returns the system clipboard, or nil if the backend has none
+	Clipboard() Clipboard
+
 	// Properties
 	Size() (width, height int)
 	SetSize(width, height int) error
@@ -5,8 +5,10 @@ package gui
 
 import (
     "fmt"
+    "image"
     "image/png"
     "os"
+    "sync"
     "time"
 
     "github.com/opd-ai/gui/graphics"
@@ -22,6 +24,10 @@ type StubRenderer struct {
     frameCount int
     lastFrame  time.Time
     events     []Event
+    wakeOnce   sync.Once
+    wake       chan struct{}
+    cursor     CursorShape
+    textInput  image.Rectangle
 }
 
 // Show displays the window (creates output directory for stub renderer)
@@ -76,6 +82,61 @@ func (r *StubRenderer) PollEvents() []Event {
     return events
 }
 
+// WaitEvents blocks until the timeout elapses or Wake is called, then returns pending events
+func (r *StubRenderer) WaitEvents(timeout time.Duration) []Event {
+    if len(r.events) > 0 || timeout == 0 {
+        return r.PollEvents()
+    }
+
+    var expired <-chan time.Time
+    if timeout > 0 {
+        timer := time.NewTimer(timeout)
+        defer timer.Stop()
+        expired = timer.C
+    }
+
+    select {
+    case <-r.wakeChan():
+    case <-expired:
+    }
+
+    return r.PollEvents()
+}
+
+// Wake interrupts a pending WaitEvents
+func (r *StubRenderer) Wake() {
+    select {
+    case r.wakeChan() <- struct{}{}:
+    default: // A wake-up is already pending
+    }
+}
+
+// wakeChan lazily creates the channel used to interrupt WaitEvents
+func (r *StubRenderer) wakeChan() chan struct{} {
+    r.wakeOnce.Do(func() {
+        r.wake = make(chan struct{}, 1)
+    })
+    return r.wake
+}
+
+// SetCursor records the cursor shape; the stub has no pointer to show it on
+func (r *StubRenderer) SetCursor(shape CursorShape) error {
+    r.cursor = shape
+    return nil
+}
+
+// SetTextInputRect records the caret rectangle; the stub has no input method
+func (r *StubRenderer) SetTextInputRect(rect image.Rectangle) error {
+    r.textInput = rect
+    return nil
+}
+
+// Clipboard returns nil; the stub has no system clipboard, so windows use
+// the in-process one
+func (r *StubRenderer) Clipboard() Clipboard {
+    return nil
+}
+
 // Size returns the window dimensions
 func (r *StubRenderer) Size() (width, height int) {
     return r.width, r.height
@@ -103,17 +164,26 @@ func (r *StubRenderer) SetSize(width, height int) error {
 
 // generateTestEvents creates simulated user interactions for testing
 func (r *StubRenderer) generateTestEvents() {
-    // Simulate some mouse clicks at different positions
-    r.events = append(r.events, 
-        NewClickEvent(100, 50, MouseButtonLeft),
-        NewClickEvent(200, 150, MouseButtonLeft),
+    // Simulate some mouse clicks at different positions; the window
+    // synthesizes clicks from matching press and release pairs
+    r.events = append(r.events,
+        NewMouseDownEvent(100, 50, MouseButtonLeft),
+        NewMouseUpEvent(100, 50, MouseButtonLeft),
+        NewMouseDownEvent(200, 150, MouseButtonLeft),
+        NewMouseMoveEvent(210, 155),
+        NewMouseUpEvent(210, 155, MouseButtonLeft),
         NewMouseMoveEvent(150, 100),
+        NewWheelEvent(150, 100, 0, 1, WheelUnitLine),
+        NewMouseLeaveEvent(150, 100),
     )
 
     // Simulate some key presses
     r.events = append(r.events,
         NewKeyPressEvent(KeyA, ModifierNone),
         NewTextInputEvent("Hello"),
+        NewKeyReleaseEvent(KeyA, ModifierNone),
+        NewKeyPressEvent(KeyHome, ModifierShift),
+        NewKeyReleaseEvent(KeyHome, ModifierShift),
     )
 }
 
@@ -124,7 +194,8 @@ type EnhancedGGCanvas struct {
 }
 
 // Present saves the current frame as a PNG file
-func (c *EnhancedGGCanvas) Present() error {
+// The stub writes whole frames, so the damaged region only decides whether a frame is written
+func (c *EnhancedGGCanvas) Present(damage image.Rectangle) error {
     if c.renderer == nil {
         return fmt.Errorf("no renderer associated with canvas")
     }
@@ -133,6 +204,10 @@ func (c *EnhancedGGCanvas) Present() error {
         return nil // Don't save frames when window is closed
     }
 
+    if damage.Empty() {
+        return nil // Nothing changed since the last frame
+    }
+
     // Get the rendered image
     img := c.GetImage()
     if img == nil {
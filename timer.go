@@ -0,0 +1,153 @@
+package gui
+
+import "time"
+
+// Timer is a callback scheduled on a window's UI loop by AfterFunc or Every
+type Timer struct {
+	window   *Window
+	fn       func()
+	interval time.Duration // Zero for one-shot timers
+	due      time.Time
+	active   bool
+	gen      uint64 // Incremented on every (re)start to discard stale events
+}
+
+// TimerEvent reports that a timer is due; handling it runs the timer's callback
+type TimerEvent struct {
+	BaseEvent
+	Timer *Timer
+	gen   uint64
+}
+
+func NewTimerEvent(timer *Timer) *TimerEvent {
+	return &TimerEvent{
+		BaseEvent: NewBaseEvent(EventTypeTimer),
+		Timer:     timer,
+		gen:       timer.gen,
+	}
+}
+
+// AfterFunc runs fn once on the UI loop after d has elapsed
+func (w *Window) AfterFunc(d time.Duration, fn func()) *Timer {
+	return w.startTimer(&Timer{window: w, fn: fn}, d)
+}
+
+// Every runs fn on the UI loop each time d elapses until the timer is stopped
+func (w *Window) Every(d time.Duration, fn func()) *Timer {
+	if d <= 0 {
+		d = time.Millisecond
+	}
+	return w.startTimer(&Timer{window: w, fn: fn, interval: d}, d)
+}
+
+// Stop cancels the timer, including a firing that is already queued;
+// it returns false if the timer had already fired or been stopped
+func (t *Timer) Stop() bool {
+	t.window.mu.Lock()
+	defer t.window.mu.Unlock()
+
+	if !t.active {
+		return false
+	}
+	t.active = false
+	t.window.removeTimerLocked(t)
+	return true
+}
+
+// Reset reschedules the timer to fire after d, restarting it if it
+// had fired or been stopped
+func (t *Timer) Reset(d time.Duration) {
+	t.Stop()
+	t.window.startTimer(t, d)
+}
+
+// IsActive reports whether the timer is still scheduled
+func (t *Timer) IsActive() bool {
+	t.window.mu.RLock()
+	defer t.window.mu.RUnlock()
+	return t.active
+}
+
+// startTimer schedules t to fire after d
+func (w *Window) startTimer(t *Timer, d time.Duration) *Timer {
+	w.mu.Lock()
+	t.due = time.Now().Add(d)
+	t.active = true
+	t.gen++
+	w.timers = append(w.timers, t)
+	w.mu.Unlock()
+
+	// Let a blocked Run recompute its deadline
+	w.renderer.Wake()
+	return t
+}
+
+// removeTimerLocked drops t from the schedule; the caller must hold w.mu
+func (w *Window) removeTimerLocked(t *Timer) {
+	for i, timer := range w.timers {
+		if timer == t {
+			w.timers = append(w.timers[:i], w.timers[i+1:]...)
+			return
+		}
+	}
+}
+
+// nextTimer returns the earliest due time of the scheduled timers
+func (w *Window) nextTimer() (due time.Time, ok bool) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	for _, t := range w.timers {
+		if !ok || t.due.Before(due) {
+			due, ok = t.due, true
+		}
+	}
+	return due, ok
+}
+
+// dueTimers returns events for timers due at now and reschedules
+// repeating timers
+func (w *Window) dueTimers(now time.Time) []Event {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	var events []Event
+	remaining := w.timers[:0]
+	for _, t := range w.timers {
+		if t.due.After(now) {
+			remaining = append(remaining, t)
+			continue
+		}
+
+		// One-shot timers stay active until their event is handled,
+		// so Stop can still cancel them
+		events = append(events, NewTimerEvent(t))
+		if t.interval > 0 {
+			// Keep a steady cadence, skipping ticks missed while busy
+			t.due = t.due.Add(t.interval)
+			if !t.due.After(now) {
+				t.due = now.Add(t.interval)
+			}
+			remaining = append(remaining, t)
+		}
+	}
+	w.timers = remaining
+
+	return events
+}
+
+// fire runs the timer's callback for a delivered TimerEvent unless the
+// timer was stopped or restarted since the event was queued
+func (t *Timer) fire(event *TimerEvent) {
+	w := t.window
+	w.mu.Lock()
+	current := t.active && t.gen == event.gen
+	if current && t.interval == 0 {
+		t.active = false
+	}
+	w.mu.Unlock()
+
+	if current && t.fn != nil {
+		t.fn()
+	}
+}
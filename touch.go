@@ -0,0 +1,166 @@
+package gui
+
+// activeTouch is a finger on the screen and the element it started on
+type activeTouch struct {
+	point  TouchPoint
+	target GUIElement // nil for the window itself
+}
+
+// touchState tracks the fingers on the screen; it is only touched on the UI loop
+type touchState struct {
+	active    []activeTouch
+	emulating bool // Whether a touch is driving synthetic mouse events
+	mouseID   int  // ID of that touch
+}
+
+// handleTouch routes a touch event to the elements its points started on,
+// then turns the first touch into mouse events if nothing handled it
+func (w *Window) handleTouch(event Event, touch *TouchEvent) bool {
+	if len(touch.Changed) == 0 {
+		return false
+	}
+
+	targets := make([]GUIElement, len(touch.Changed))
+	for i, point := range touch.Changed {
+		targets[i] = w.touchTarget(event, point)
+	}
+	w.updateTouches(event, touch.Changed)
+	touch.Touches = w.Touches()
+
+	handled := w.dispatchTouch(event, touch, targets)
+	w.emulateMouse(event, touch, handled)
+	return handled
+}
+
+// Touches returns the points currently on the screen
+func (w *Window) Touches() []TouchPoint {
+	points := make([]TouchPoint, len(w.touch.active))
+	for i, t := range w.touch.active {
+		points[i] = t.point
+	}
+	return points
+}
+
+// touchTarget returns the element a touch point belongs to: the element
+// under it for new touches, otherwise the one it started on
+func (w *Window) touchTarget(event Event, point TouchPoint) GUIElement {
+	if _, ok := event.(*TouchStartEvent); !ok {
+		for _, t := range w.touch.active {
+			if t.point.ID == point.ID {
+				return t.target
+			}
+		}
+	}
+	return w.Element.elementAt(point.X, point.Y)
+}
+
+// updateTouches adds, moves or removes the changed points
+func (w *Window) updateTouches(event Event, changed []TouchPoint) {
+	for _, point := range changed {
+		index := -1
+		for i, t := range w.touch.active {
+			if t.point.ID == point.ID {
+				index = i
+				break
+			}
+		}
+
+		switch event.(type) {
+		case *TouchStartEvent:
+			if index < 0 {
+				w.touch.active = append(w.touch.active, activeTouch{point: point, target: w.Element.elementAt(point.X, point.Y)})
+			}
+		case *TouchMoveEvent:
+			if index >= 0 {
+				w.touch.active[index].point = point
+			}
+		default:
+			if index >= 0 {
+				w.touch.active = append(w.touch.active[:index], w.touch.active[index+1:]...)
+			}
+		}
+	}
+}
+
+// dispatchTouch delivers the event once per target, with Changed and the
+// position narrowed to the points belonging to that target
+func (w *Window) dispatchTouch(event Event, touch *TouchEvent, targets []GUIElement) bool {
+	all := touch.Changed
+	defer func() {
+		touch.Changed = all
+		touch.X, touch.Y = all[0].X, all[0].Y
+	}()
+
+	handled := false
+	done := make([]bool, len(all))
+	for i := range all {
+		if done[i] {
+			continue
+		}
+
+		var group []TouchPoint
+		for j := i; j < len(all); j++ {
+			if !done[j] && targets[j] == targets[i] {
+				group = append(group, all[j])
+				done[j] = true
+			}
+		}
+
+		touch.Changed = group
+		touch.X, touch.Y = group[0].X, group[0].Y
+		if w.deliverTo(targets[i], event) {
+			handled = true
+		}
+	}
+	return handled
+}
+
+// emulateMouse turns an unhandled first touch into mouse move, down and
+// up events, so elements written for the mouse work on touchscreens
+func (w *Window) emulateMouse(event Event, touch *TouchEvent, handled bool) {
+	t := &w.touch
+
+	if _, ok := event.(*TouchStartEvent); ok {
+		// Only a first finger landing on an element that ignored it
+		if t.emulating || handled || event.IsDefaultPrevented() || len(touch.Touches) != len(touch.Changed) {
+			return
+		}
+		point := touch.Changed[0]
+		t.emulating = true
+		t.mouseID = point.ID
+		w.handleMouseMove(NewMouseMoveEvent(point.X, point.Y))
+		w.handleMouseDown(NewMouseDownEvent(point.X, point.Y, MouseButtonLeft))
+		return
+	}
+
+	if !t.emulating {
+		return
+	}
+	for _, point := range touch.Changed {
+		if point.ID != t.mouseID {
+			continue
+		}
+
+		_, moving := event.(*TouchMoveEvent)
+		_, cancelled := event.(*TouchCancelEvent)
+
+		// A touch claimed midway, as by a pan, stops driving the mouse
+		if moving && handled {
+			moving, cancelled = false, true
+		}
+
+		if moving {
+			w.handleMouseMove(NewMouseMoveEvent(point.X, point.Y))
+		} else {
+			up := NewMouseUpEvent(point.X, point.Y, MouseButtonLeft)
+			if cancelled {
+				up.PreventDefault() // No click for a touch that was taken away
+			}
+			w.handleMouseUp(up)
+			t.emulating = false
+
+			// A lifted finger hovers over nothing
+			w.updateHover(nil, point.X, point.Y)
+		}
+	}
+}
@@ -0,0 +1,235 @@
+package gui
+
+import (
+	"image"
+	"math"
+)
+
+// Matrix is a 2D affine transform mapping (x, y) to
+// (A*x + C*y + E, B*x + D*y + F)
+type Matrix struct {
+	A, B, C, D, E, F float64
+}
+
+// IdentityMatrix returns the transform that changes nothing
+func IdentityMatrix() Matrix {
+	return Matrix{A: 1, D: 1}
+}
+
+// TranslationMatrix returns a transform moving points by dx, dy
+func TranslationMatrix(dx, dy float64) Matrix {
+	return Matrix{A: 1, D: 1, E: dx, F: dy}
+}
+
+// ScalingMatrix returns a transform scaling points about the origin
+func ScalingMatrix(sx, sy float64) Matrix {
+	return Matrix{A: sx, D: sy}
+}
+
+// RotationMatrix returns a transform rotating points clockwise on screen
+// about the origin, by an angle in radians
+func RotationMatrix(radians float64) Matrix {
+	sin, cos := math.Sincos(radians)
+	return Matrix{A: cos, B: sin, C: -sin, D: cos}
+}
+
+// Multiply returns the transform applying n first, then m
+func (m Matrix) Multiply(n Matrix) Matrix {
+	return Matrix{
+		A: m.A*n.A + m.C*n.B,
+		B: m.B*n.A + m.D*n.B,
+		C: m.A*n.C + m.C*n.D,
+		D: m.B*n.C + m.D*n.D,
+		E: m.A*n.E + m.C*n.F + m.E,
+		F: m.B*n.E + m.D*n.F + m.F,
+	}
+}
+
+// Apply transforms a point
+func (m Matrix) Apply(x, y float64) (float64, float64) {
+	return m.A*x + m.C*y + m.E, m.B*x + m.D*y + m.F
+}
+
+// Invert returns the inverse transform; singular matrices, such as a zero
+// scale, invert to a transform collapsing everything far off screen
+func (m Matrix) Invert() Matrix {
+	det := m.A*m.D - m.B*m.C
+	if det == 0 {
+		return Matrix{E: math.Inf(1), F: math.Inf(1)}
+	}
+	return Matrix{
+		A: m.D / det,
+		B: -m.B / det,
+		C: -m.C / det,
+		D: m.A / det,
+		E: (m.C*m.F - m.D*m.E) / det,
+		F: (m.B*m.E - m.A*m.F) / det,
+	}
+}
+
+// IsTranslation reports whether the transform only moves points
+func (m Matrix) IsTranslation() bool {
+	return m.A == 1 && m.B == 0 && m.C == 0 && m.D == 1
+}
+
+// TransformRect returns the smallest rectangle containing a transformed rectangle
+func (m Matrix) TransformRect(r image.Rectangle) image.Rectangle {
+	if r.Empty() {
+		return image.Rectangle{}
+	}
+	if m.IsTranslation() && m.E == math.Trunc(m.E) && m.F == math.Trunc(m.F) {
+		return r.Add(image.Pt(int(m.E), int(m.F)))
+	}
+
+	minX, minY := math.Inf(1), math.Inf(1)
+	maxX, maxY := math.Inf(-1), math.Inf(-1)
+	for _, corner := range [4]image.Point{r.Min, {r.Max.X, r.Min.Y}, r.Max, {r.Min.X, r.Max.Y}} {
+		x, y := m.Apply(float64(corner.X), float64(corner.Y))
+		minX, maxX = math.Min(minX, x), math.Max(maxX, x)
+		minY, maxY = math.Min(minY, y), math.Max(maxY, y)
+	}
+	if math.IsInf(minX, 0) || math.IsInf(minY, 0) {
+		return image.Rectangle{}
+	}
+	return image.Rect(int(math.Floor(minX)), int(math.Floor(minY)), int(math.Ceil(maxX)), int(math.Ceil(maxY)))
+}
+
+// SetScale scales the element's rendering and hit area about its center
+func (e *Element) SetScale(sx, sy float64) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.scaleX == sx && e.scaleY == sy {
+		return
+	}
+	e.invalidateLocked(e.visualBoundsLocked())
+	e.scaleX, e.scaleY = sx, sy
+	e.invalidateLocked(e.visualBoundsLocked())
+}
+
+// Scale returns the element's horizontal and vertical scale factors
+func (e *Element) Scale() (sx, sy float64) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.scaleX, e.scaleY
+}
+
+// SetRotation rotates the element's rendering and hit area about its
+// center, clockwise on screen, by an angle in radians
+func (e *Element) SetRotation(radians float64) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.rotation == radians {
+		return
+	}
+	e.invalidateLocked(e.visualBoundsLocked())
+	e.rotation = radians
+	e.invalidateLocked(e.visualBoundsLocked())
+}
+
+// Rotation returns the element's rotation in radians
+func (e *Element) Rotation() float64 {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.rotation
+}
+
+// hasTransformLocked reports whether the element is scaled or rotated; the
+// caller must hold e.mu
+func (e *Element) hasTransformLocked() bool {
+	return e.scaleX != 1 || e.scaleY != 1 || e.rotation != 0
+}
+
+// effectLocked returns the element's scale and rotation about its center,
+// in its parent's coordinates; the caller must hold e.mu
+func (e *Element) effectLocked() Matrix {
+	if !e.hasTransformLocked() {
+		return IdentityMatrix()
+	}
+	cx := float64(e.x) + float64(e.width)/2
+	cy := float64(e.y) + float64(e.height)/2
+	return TranslationMatrix(cx, cy).
+		Multiply(RotationMatrix(e.rotation)).
+		Multiply(ScalingMatrix(e.scaleX, e.scaleY)).
+		Multiply(TranslationMatrix(-cx, -cy))
+}
+
+// toParentLocked maps the element's local coordinates, whose origin is its
+// top-left corner, to its parent's; the caller must hold e.mu
+func (e *Element) toParentLocked() Matrix {
+	return e.effectLocked().Multiply(TranslationMatrix(float64(e.x), float64(e.y)))
+}
+
+// visualBoundsLocked returns the area the element covers in its parent,
+// after scaling and rotation; the caller must hold e.mu
+func (e *Element) visualBoundsLocked() image.Rectangle {
+	return e.toParentLocked().TransformRect(image.Rect(0, 0, e.width, e.height))
+}
+
+// toParent returns the element's local to parent transform
+func (e *Element) toParent() Matrix {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.toParentLocked()
+}
+
+// toWindow returns the transform from the element's local coordinates to
+// the window's, combining the transforms of all its ancestors
+func (e *Element) toWindow() Matrix {
+	m := e.toParent()
+	for parent := e.parentElement(); parent != nil; parent = parent.parentElement() {
+		m = parent.toParent().Multiply(m)
+	}
+	return m
+}
+
+// LocalToWindow converts a point relative to the element's top-left
+// corner into window coordinates
+func (e *Element) LocalToWindow(x, y int) (int, int) {
+	wx, wy := e.toWindow().Apply(float64(x), float64(y))
+	return int(math.Floor(wx + 0.5)), int(math.Floor(wy + 0.5))
+}
+
+// WindowToLocal converts a point in window coordinates into one relative
+// to the element's top-left corner
+func (e *Element) WindowToLocal(x, y int) (int, int) {
+	lx, ly := e.toWindow().Invert().Apply(float64(x), float64(y))
+	return int(math.Floor(lx)), int(math.Floor(ly))
+}
+
+// visualBoundsOf returns the area a GUIElement covers in its parent
+func visualBoundsOf(el GUIElement) image.Rectangle {
+	if base := baseOf(el); base != nil {
+		base.mu.RLock()
+		defer base.mu.RUnlock()
+		return base.visualBoundsLocked()
+	}
+	return boundsOf(el)
+}
+
+// renderChild renders a child in its parent's coordinates, applying its
+// scale and rotation
+func renderChild(canvas Canvas, child GUIElement) error {
+	base := baseOf(child)
+	if base == nil {
+		return child.Render(canvas)
+	}
+
+	base.mu.RLock()
+	transformed := base.hasTransformLocked()
+	cx := float64(base.x) + float64(base.width)/2
+	cy := float64(base.y) + float64(base.height)/2
+	scaleX, scaleY, rotation := base.scaleX, base.scaleY, base.rotation
+	base.mu.RUnlock()
+
+	if !transformed {
+		return child.Render(canvas)
+	}
+
+	canvas.Push()
+	defer canvas.Pop()
+	canvas.Translate(cx, cy)
+	canvas.Rotate(rotation)
+	canvas.Scale(scaleX, scaleY)
+	canvas.Translate(-cx, -cy)
+	return child.Render(canvas)
+}
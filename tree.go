@@ -0,0 +1,228 @@
+package gui
+
+// InsertChild inserts a child at index in z-order, 0 being the bottom,
+// detaching it from its previous parent; out of range indices append
+func (e *Element) InsertChild(index int, child GUIElement) {
+	e.insertChild(index, child)
+}
+
+// insertChild detaches child from its parent and inserts it at index, or
+// on top for a negative index
+func (e *Element) insertChild(index int, child GUIElement) {
+	base := baseOf(child)
+	if base != nil {
+		for ancestor := e; ancestor != nil; ancestor = ancestor.parentElement() {
+			if ancestor == base {
+				panic("gui: cannot add an element to itself or its descendants")
+			}
+		}
+		if old := base.parentElement(); old == e {
+			e.removeChild(child) // Reordering keeps the child's layout settings
+		} else if old != nil {
+			old.RemoveChild(child)
+		}
+	} else {
+		e.removeChild(child) // Without a base only a duplicate here can be detected
+	}
+
+	e.mu.Lock()
+	if index < 0 || index > len(e.children) {
+		index = len(e.children)
+	}
+	e.children = append(e.children, nil)
+	copy(e.children[index+1:], e.children[index:])
+	e.children[index] = child
+	e.invalidateLocked(e.toParentLocked().TransformRect(visualBoundsOf(child)))
+	e.mu.Unlock()
+
+	if base != nil {
+		base.mu.Lock()
+		base.parent = e
+		base.owner = child
+		base.mu.Unlock()
+	}
+	e.InvalidateLayout()
+}
+
+// setParent records the element's parent, nil when it is detached
+func (e *Element) setParent(parent *Element) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.parent = parent
+}
+
+// parentElement returns the base of the element's parent, or nil
+func (e *Element) parentElement() *Element {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.parent
+}
+
+// self returns the outermost value embedding the element, or the element
+// itself when it has never been added to a parent
+func (e *Element) self() GUIElement {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	if e.owner != nil {
+		return e.owner
+	}
+	return e
+}
+
+// Parent returns the element this one is a child of, or nil
+func (e *Element) Parent() GUIElement {
+	parent := e.parentElement()
+	if parent == nil {
+		return nil
+	}
+	return parent.self()
+}
+
+// Children returns a copy of the child list, bottom to top in z-order
+func (e *Element) Children() []GUIElement {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.snapshotChildren()
+}
+
+// ChildIndex returns the z-order position of a child, or -1
+func (e *Element) ChildIndex(child GUIElement) int {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	for i, c := range e.children {
+		if c == child {
+			return i
+		}
+	}
+	return -1
+}
+
+// MoveChild moves a child to index in z-order, clamped to the child list;
+// layouts that place children in list order arrange them again
+func (e *Element) MoveChild(child GUIElement, index int) {
+	if e.moveChild(child, index) {
+		e.InvalidateLayout()
+	}
+}
+
+// moveChild reorders the child list and reports whether the child moved
+func (e *Element) moveChild(child GUIElement, index int) bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	from := -1
+	for i, c := range e.children {
+		if c == child {
+			from = i
+			break
+		}
+	}
+	if from < 0 {
+		return false
+	}
+	if index < 0 {
+		index = 0
+	}
+	if index >= len(e.children) {
+		index = len(e.children) - 1
+	}
+	if index == from {
+		return false
+	}
+
+	if from < index {
+		copy(e.children[from:index], e.children[from+1:index+1])
+	} else {
+		copy(e.children[index+1:from+1], e.children[index:from])
+	}
+	e.children[index] = child
+	e.invalidateLocked(e.toParentLocked().TransformRect(visualBoundsOf(child)))
+	return true
+}
+
+// RaiseToTop moves the element above its siblings
+func (e *Element) RaiseToTop() {
+	if parent := e.parentElement(); parent != nil {
+		parent.MoveChild(e.self(), len(parent.Children())-1)
+	}
+}
+
+// LowerToBottom moves the element below its siblings
+func (e *Element) LowerToBottom() {
+	if parent := e.parentElement(); parent != nil {
+		parent.MoveChild(e.self(), 0)
+	}
+}
+
+// Walk visits the element's descendants depth first, parents before their
+// children and bottom to top in z-order, until fn returns false
+func (e *Element) Walk(fn func(el GUIElement) bool) {
+	e.walk(fn)
+}
+
+// walk visits descendants and reports whether the walk should go on
+func (e *Element) walk(fn func(el GUIElement) bool) bool {
+	for _, child := range e.Children() {
+		if !fn(child) {
+			return false
+		}
+		if base := baseOf(child); base != nil && !base.walk(fn) {
+			return false
+		}
+	}
+	return true
+}
+
+// Find returns the first descendant, in Walk order, for which match
+// returns true, or nil
+func (e *Element) Find(match func(el GUIElement) bool) GUIElement {
+	var found GUIElement
+	e.Walk(func(el GUIElement) bool {
+		if match(el) {
+			found = el
+			return false
+		}
+		return true
+	})
+	return found
+}
+
+// Ancestors returns the element's ancestors, nearest first
+func (e *Element) Ancestors() []GUIElement {
+	var ancestors []GUIElement
+	for parent := e.parentElement(); parent != nil; parent = parent.parentElement() {
+		ancestors = append(ancestors, parent.self())
+	}
+	return ancestors
+}
+
+// IsAncestorOf reports whether el is a descendant of the element
+func (e *Element) IsAncestorOf(el GUIElement) bool {
+	base := baseOf(el)
+	if base == nil {
+		// Elements without a base have no parent pointer to follow
+		_, ok := e.pathTo(el)
+		return ok
+	}
+	for parent := base.parentElement(); parent != nil; parent = parent.parentElement() {
+		if parent == e {
+			return true
+		}
+	}
+	return false
+}
+
+// Root returns the topmost ancestor, or the element itself when it has no parent
+func (e *Element) Root() GUIElement {
+	root := e
+	for parent := e.parentElement(); parent != nil; parent = parent.parentElement() {
+		root = parent
+	}
+	return root.self()
+}
+
+// Window returns the window the element is mounted in, or nil
+func (e *Element) Window() *Window {
+	w, _ := e.Root().(*Window)
+	return w
+}